@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dkaslovsky/GoThinkBayes/exercises"
+	"github.com/dkaslovsky/GoThinkBayes/spec"
+)
+
+// exit codes
+const (
+	exitOK    = 0
+	exitError = 1 // an exercise or spec failed
+	exitUsage = 2 // invalid command, exercise or flags
+)
+
+const usage = `Usage: GoThinkBayes <command> [arguments]
+
+Commands:
+  list                       list the exercises
+  run [flags] <exercise>...  run the named exercises
+  run [flags] --all          run all exercises
+  run-spec [flags] <file>... run problems specified in JSON files (YAML is not supported)
+
+Run "GoThinkBayes <command> --help" for the flags of the run and run-spec commands.
+`
+
+// run runs the command line with the specified arguments and returns the exit code
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "list":
+		return runList(args[1:], stdout, stderr)
+	case "run":
+		return runExercises(args[1:], stdout, stderr)
+	case "run-spec":
+		return runSpecs(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command [%s]\n\n%s", args[0], usage)
+		return exitUsage
+	}
+}
+
+func runList(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) > 0 {
+		fmt.Fprintf(stderr, "list takes no arguments\n")
+		return exitUsage
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	for _, e := range exercises.All() {
+		fmt.Fprintf(w, "%s\t%s\n", e.Name, e.Description)
+	}
+	w.Flush()
+	return exitOK
+}
+
+func runExercises(args []string, stdout io.Writer, stderr io.Writer) int {
+	cfg := exercises.DefaultConfig()
+	bounds := intList(cfg.LocomotiveBounds)
+
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	all := fs.Bool("all", false, "run all exercises")
+	fs.Var(&bounds, "bounds", "comma-separated upper bounds of the locomotive priors")
+	fs.Int64Var(&cfg.EuroHeads, "heads", cfg.EuroHeads, "number of heads observed in the Euro problem")
+	fs.Int64Var(&cfg.EuroTails, "tails", cfg.EuroTails, "number of tails observed in the Euro problem")
+	fs.StringVar(&cfg.Prior, "prior", cfg.Prior,
+		"prior family (uniform, triangle, powerlaw) of the exercises supporting it (locomotive: all; euro: uniform, triangle); "+
+			"defaults to each exercise's families, and --all skips exercises not supporting it")
+	fs.Float64Var(&cfg.CILength, "ci", cfg.CILength, "length (percent) of credible intervals")
+	format := fs.String("format", exercises.TextFormat, "output format (text, json, csv)")
+
+	names, err := parseInterleaved(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+	cfg.LocomotiveBounds = bounds
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(stderr, "invalid flags: %v\n", err)
+		return exitUsage
+	}
+	if !slices.Contains(exercises.Formats, *format) {
+		fmt.Fprintf(stderr, "invalid flags: unknown format [%s]\n", *format)
+		return exitUsage
+	}
+
+	selected, err := selectExercises(names, *all, cfg.Prior)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n\n%s", err, usage)
+		return exitUsage
+	}
+
+	reports := []*exercises.Report{}
+	for _, e := range selected {
+		report, err := e.Run(cfg)
+		if err != nil {
+			fmt.Fprintf(stderr, "exercise [%s] failed: %v\n", e.Name, err)
+			return exitError
+		}
+		reports = append(reports, report)
+	}
+	if err := exercises.Write(stdout, *format, reports); err != nil {
+		fmt.Fprintf(stderr, "unable to write results: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func runSpecs(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("run-spec", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	posterior := fs.Bool("posterior", false, "print the posterior distribution")
+
+	paths, err := parseInterleaved(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+	if len(paths) == 0 {
+		fmt.Fprintf(stderr, "no spec files specified\n\n%s", usage)
+		return exitUsage
+	}
+
+	for _, path := range paths {
+		s, err := spec.LoadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "spec [%s] failed: %v\n", path, err)
+			return exitError
+		}
+		result, err := s.Run()
+		if err != nil {
+			fmt.Fprintf(stderr, "spec [%s] failed: %v\n", path, err)
+			return exitError
+		}
+
+		fmt.Fprintf(stdout, "%s:\n", result.Name)
+		if *posterior {
+			result.Posterior.Fprint(stdout)
+		}
+		result.Print(stdout)
+		fmt.Fprintln(stdout)
+	}
+	return exitOK
+}
+
+// selectExercises returns the named exercises, or all exercises supporting the prior family
+func selectExercises(names []string, all bool, prior string) ([]*exercises.Exercise, error) {
+	if all {
+		if len(names) > 0 {
+			return nil, fmt.Errorf("cannot specify exercises with --all")
+		}
+		selected := []*exercises.Exercise{}
+		for _, e := range exercises.All() {
+			if e.SupportsPrior(prior) {
+				selected = append(selected, e)
+			}
+		}
+		return selected, nil
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no exercises specified")
+	}
+
+	selected := []*exercises.Exercise{}
+	for _, name := range names {
+		e, err := exercises.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		if !e.SupportsPrior(prior) {
+			return nil, fmt.Errorf("exercise [%s] does not support prior family [%s]", name, prior)
+		}
+		selected = append(selected, e)
+	}
+	return selected, nil
+}
+
+// parseInterleaved parses flags appearing before, between or after positional arguments
+// and returns the positional arguments
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// intList is a flag.Value holding a comma-separated list of integers
+type intList []int
+
+func (l *intList) String() string {
+	vals := make([]string, 0, len(*l))
+	for _, val := range *l {
+		vals = append(vals, strconv.Itoa(val))
+	}
+	return strings.Join(vals, ",")
+}
+
+// Set replaces the list with the parsed values
+func (l *intList) Set(s string) error {
+	vals := intList{}
+	for _, field := range strings.Split(s, ",") {
+		val, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return fmt.Errorf("invalid integer [%s]", field)
+		}
+		vals = append(vals, val)
+	}
+	*l = vals
+	return nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dkaslovsky/GoThinkBayes/exercises"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected int
+	}{
+		"no command": {
+			args:     []string{},
+			expected: exitUsage,
+		},
+		"unknown command": {
+			args:     []string{"foo"},
+			expected: exitUsage,
+		},
+		"help": {
+			args:     []string{"help"},
+			expected: exitOK,
+		},
+		"list with arguments": {
+			args:     []string{"list", "dice"},
+			expected: exitUsage,
+		},
+		"run without exercises": {
+			args:     []string{"run"},
+			expected: exitUsage,
+		},
+		"run unknown exercise": {
+			args:     []string{"run", "foo"},
+			expected: exitUsage,
+		},
+		"run all with exercises": {
+			args:     []string{"run", "--all", "dice"},
+			expected: exitUsage,
+		},
+		"run with unknown flag": {
+			args:     []string{"run", "--foo", "dice"},
+			expected: exitUsage,
+		},
+		"run with invalid bounds": {
+			args:     []string{"run", "locomotive", "--bounds", "100,x"},
+			expected: exitUsage,
+		},
+		"run with invalid credible interval": {
+			args:     []string{"run", "--ci", "0", "euro"},
+			expected: exitUsage,
+		},
+		"run with unknown prior": {
+			args:     []string{"run", "--prior", "foo", "euro"},
+			expected: exitUsage,
+		},
+		"run with unsupported prior": {
+			args:     []string{"run", "--prior", "powerlaw", "euro"},
+			expected: exitUsage,
+		},
+		"run all with prior": {
+			args:     []string{"run", "--all", "--prior", "powerlaw"},
+			expected: exitOK,
+		},
+		"run with unknown format": {
+			args:     []string{"run", "--format", "yaml", "euro"},
+			expected: exitUsage,
+		},
+		"run help": {
+			args:     []string{"run", "--help"},
+			expected: exitOK,
+		},
+		"run-spec without files": {
+			args:     []string{"run-spec"},
+			expected: exitUsage,
+		},
+		"run-spec with missing file": {
+			args:     []string{"run-spec", "specs/missing.json"},
+			expected: exitError,
+		},
+		"run-spec": {
+			args:     []string{"run-spec", "specs/dice.json", "--posterior", "specs/euro.json"},
+			expected: exitOK,
+		},
+		"run euro with many flips": {
+			args:     []string{"run", "euro", "--heads", "1000", "--tails", "1000"},
+			expected: exitOK,
+		},
+		"run with full credible interval": {
+			args:     []string{"run", "locomotive", "euro", "--ci", "100"},
+			expected: exitOK,
+		},
+		"run exercises": {
+			args:     []string{"run", "dice", "locomotive", "--bounds", "100,200", "--prior", "triangle"},
+			expected: exitOK,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			code := run(test.args, stdout, stderr)
+			assert.Equal(t, test.expected, code)
+			if code != exitOK {
+				assert.NotEmpty(t, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunList(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	require.Equal(t, exitOK, run([]string{"list"}, stdout, stderr))
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 8)
+	assert.True(t, strings.HasPrefix(lines[0], "cookie-manual "))
+	assert.True(t, strings.HasPrefix(lines[7], "euro "))
+}
+
+func TestRunAllSkipsUnsupportedPrior(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	require.Equal(t, exitOK, run([]string{"run", "--all", "--prior", "powerlaw"}, stdout, stderr))
+	assert.Contains(t, stdout.String(), "Cookie:\n")
+	assert.Contains(t, stdout.String(), "Power Law Single Observation\n")
+	assert.NotContains(t, stdout.String(), "Euro:\n")
+}
+
+func TestRunLocomotiveDefaultSections(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	require.Equal(t, exitOK, run([]string{"run", "locomotive"}, stdout, stderr))
+
+	sections := []string{}
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line != "" && !strings.HasPrefix(line, "Upper bound") {
+			sections = append(sections, line)
+		}
+	}
+	assert.Equal(t, []string{
+		"Locomotive:",
+		"Uniform Single Observation",
+		"Uniform Multiple Observation",
+		"Power Law Single Observation",
+		"Power Law Multiple Observation",
+		"Uniform Censored Observation (at least 60)",
+	}, sections)
+	assert.Contains(t, stdout.String(), "Uniform Multiple Observation\nUpper bound: 500, Posterior mean: 151.85\n")
+	assert.Contains(t, stdout.String(), "Power Law Multiple Observation\nUpper bound: 500, Posterior mean: 130.71 [90% Credible Interval")
+}
+
+func TestRunFormat(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		args := []string{"run", "--format", "json", "cookie", "euro", "--prior", "uniform"}
+		require.Equal(t, exitOK, run(args, stdout, stderr))
+
+		reports := []*exercises.Report{}
+		require.Nil(t, json.Unmarshal(stdout.Bytes(), &reports))
+		require.Len(t, reports, 2)
+		assert.Equal(t, "cookie", reports[0].Exercise)
+		assert.Equal(t, "Bowl 2", reports[0].Results[0].Posterior[1].Hypothesis)
+
+		summary := reports[1].Results[0].Summary
+		require.NotNil(t, summary)
+		assert.Equal(t, 56.0, summary.MAP)
+		assert.Equal(t, 90.0, summary.CILength)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		require.Equal(t, exitOK, run([]string{"run", "--format", "csv", "monty-hall"}, stdout, stderr))
+
+		records, err := csv.NewReader(stdout).ReadAll()
+		require.Nil(t, err)
+		assert.Equal(t, []string{"exercise", "section", "label", "quantity", "hypothesis", "value"}, records[0])
+		require.Len(t, records, 4)
+		assert.Equal(t, []string{"monty-hall", "posterior", "", "posterior", "door B", "0"}, records[2])
+	})
+
+	t.Run("text", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		require.Equal(t, exitOK, run([]string{"run", "monty-hall"}, stdout, stderr))
+		assert.Equal(t, "Monty Hall:\n----------\ndoor A: 0.33\ndoor B: 0.00\ndoor C: 0.67\n----------\n\n", stdout.String())
+	})
+}
+
+func TestRunSpecPosterior(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	require.Equal(t, exitOK, run([]string{"run-spec", "--posterior", "specs/dice.json"}, stdout, stderr))
+	assert.Contains(t, stdout.String(), "----------\n4: 0.000000\n")
+	assert.Contains(t, stdout.String(), "\n20: ")
+}
+
+func TestIntList(t *testing.T) {
+	l := intList{1, 2}
+	assert.Equal(t, "1,2", l.String())
+
+	require.Nil(t, l.Set("3, 4,5"))
+	assert.Equal(t, intList{3, 4, 5}, l)
+
+	assert.NotNil(t, l.Set("3,"))
+}
@@ -36,44 +36,25 @@ var (
 	}
 )
 
-var cookieHypos = map[string]cookieHypothesis{
-	bowl1.hypo.Name: bowl1,
-	bowl2.hypo.Name: bowl2,
-}
-
-// an observation is the name (flavor) of cookie observed
-type cookieObservation struct {
-	name string
-}
-
-// Getlikelihood is the likelihood function for the Cookie problem
-func (o *cookieObservation) GetLikelihood(hypoName string) float64 {
-	hypo, ok := cookieHypos[hypoName]
-	if !ok {
-		return 0
-	}
-
-	like, ok := hypo.bowl[o.name]
-	if !ok {
-		return 0
-	}
-	return like
+// likelihood of each flavor of cookie (observation) given each bowl (hypothesis)
+var cookieLikelihoods = prob.NamedLikelihoodTable{
+	bowl1.hypo.Name: bowl1.bowl,
+	bowl2.hypo.Name: bowl2.bowl,
 }
 
 // Cookie computes the probability (after many other observations) using a suite of hypotheses
-func Cookie() {
+func Cookie() []*Result {
 	s := prob.NewNamedSuite(bowl1.hypo, bowl2.hypo)
 	obs := []prob.NamedSuiteObservation{
-		&cookieObservation{name: "vanilla"},
-		&cookieObservation{name: "chocolate"},
-		&cookieObservation{name: "vanilla"},
-		&cookieObservation{name: "chocolate"},
-		&cookieObservation{name: "chocolate"},
-		&cookieObservation{name: "chocolate"},
-		&cookieObservation{name: "vanilla"},
-		&cookieObservation{name: "chocolate"},
+		cookieLikelihoods.Observe("vanilla"),
+		cookieLikelihoods.Observe("chocolate"),
+		cookieLikelihoods.Observe("vanilla"),
+		cookieLikelihoods.Observe("chocolate"),
+		cookieLikelihoods.Observe("chocolate"),
+		cookieLikelihoods.Observe("chocolate"),
+		cookieLikelihoods.Observe("vanilla"),
+		cookieLikelihoods.Observe("chocolate"),
 	}
 	s.UpdateSet(obs)
-
-	s.Print()
+	return []*Result{{Section: "posterior", Posterior: namedPmfPosterior(s.NamedPmf)}}
 }
@@ -11,7 +11,7 @@ import (
 // What is the probability that it came from Bowl 1?
 
 // CookieManual computes the probability by manually multiplying the priors by the likelihoods
-func CookieManual() {
+func CookieManual() []*Result {
 
 	// prior distribution
 	p := prob.NewNamedPmf()
@@ -25,5 +25,5 @@ func CookieManual() {
 
 	// Renormalize to obtain the posterior distribution
 	p.Normalize()
-	p.Print()
+	return []*Result{{Section: "posterior", Posterior: namedPmfPosterior(p)}}
 }
@@ -1,6 +1,9 @@
 package exercises
 
 import (
+	"fmt"
+	"io"
+
 	"github.com/dkaslovsky/GoThinkBayes/prob"
 )
 
@@ -24,7 +27,7 @@ func (o *diceObservation) GetLikelihood(hypo float64) float64 {
 }
 
 // Dice runs the dice problem
-func Dice() {
+func Dice() []*Result {
 	s := prob.NewSuite(
 		prob.NewPmfElement(4, 1),
 		prob.NewPmfElement(6, 1),
@@ -43,6 +46,71 @@ func Dice() {
 		&diceObservation{4},
 	}
 	s.UpdateSet(obs)
+	return []*Result{{Section: "posterior", Posterior: pmfPosterior(s.Pmf)}}
+}
+
+// Dice Casino Problem:
+// A casino secretly switches between a 6-sided die and a 12-sided die, keeping the same die
+// for the next roll with probability 0.95.
+// Given a sequence of rolls, which die was used for each roll?
+
+// diceCasinoTransition is the probability of the casino switching from one die to another between rolls
+func diceCasinoTransition(from float64, to float64) float64 {
+	if from == to {
+		return 0.95
+	}
+	return 0.05
+}
 
-	s.Print()
+// DiceCasino runs the dice casino problem
+func DiceCasino() ([]*Result, error) {
+	s := prob.NewSuite(
+		prob.NewPmfElement(6, 1),
+		prob.NewPmfElement(12, 1),
+	)
+	h, err := prob.NewHMM(s, diceCasinoTransition)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create hidden Markov model: %v", err)
+	}
+
+	rolls := []float64{3, 5, 2, 6, 1, 4, 11, 9, 7, 12, 3, 10, 2, 5, 1, 6, 4}
+	obs := []prob.SuiteObservation{}
+	for _, roll := range rolls {
+		obs = append(obs, &diceObservation{roll})
+	}
+
+	posteriors, err := h.Smooth(obs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute posteriors: %v", err)
+	}
+	path, err := h.Viterbi(obs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute most likely dice: %v", err)
+	}
+
+	results := []*Result{}
+	for i, roll := range rolls {
+		results = append(results, &Result{
+			Section:   "rolls",
+			Label:     fmt.Sprintf("roll %d", i+1),
+			Posterior: pmfPosterior(posteriors[i]),
+			Values: map[string]float64{
+				"roll":          roll,
+				"mostLikelyDie": path[i],
+			},
+		})
+	}
+	return results, nil
+}
+
+// writeDiceCasinoText writes the posterior probability of the 6-sided die and the most likely die
+// for each roll
+func writeDiceCasinoText(w io.Writer, results []*Result) {
+	for i, r := range results {
+		fmt.Fprintf(
+			w, "Roll %2d: %2.0f, P(6-sided): %0.2f, most likely die: %2.0f-sided\n",
+			i+1, r.Values["roll"], r.prob("6"), r.Values["mostLikelyDie"],
+		)
+	}
+	fmt.Fprintln(w)
 }
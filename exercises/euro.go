@@ -2,6 +2,7 @@ package exercises
 
 import (
 	"fmt"
+	"io"
 	"math"
 
 	"github.com/dkaslovsky/GoThinkBayes/prob"
@@ -28,26 +29,52 @@ func (o *euroObservation) GetLikelihood(hypo float64) float64 {
 	return 0
 }
 
-func generateObs(nHeads, nTails int64) (obs []prob.SuiteObservation) {
-	heads := &euroObservation{side: "H"}
-	tails := &euroObservation{side: "T"}
+// euroCounts returns the number of times each side of the coin was observed
+func euroCounts(nHeads, nTails int64) map[prob.SuiteObservation]int {
+	return map[prob.SuiteObservation]int{
+		&euroObservation{side: "H"}: int(nHeads),
+		&euroObservation{side: "T"}: int(nTails),
+	}
+}
+
+// euroPrior is a prior over the probability (in percent) of heads
+type euroPrior struct {
+	name  string
+	title string
+	hypos []*prob.PmfElement
+}
 
-	var i int64
-	for i = 1; i <= nHeads; i++ {
-		obs = append(obs, heads)
+// euroPriors returns the priors selected by the config
+func euroPriors(cfg *Config) ([]*euroPrior, error) {
+	priors := []*euroPrior{}
+	if cfg.usesPrior(UniformPrior) {
+		priors = append(priors, &euroPrior{
+			name:  UniformPrior,
+			title: "Uniform",
+			hypos: prob.Uniform(prob.NewBound(0, 100)),
+		})
 	}
-	for i = 1; i <= nTails; i++ {
-		obs = append(obs, tails)
+	if cfg.usesPrior(TrianglePrior) {
+		priors = append(priors, &euroPrior{
+			name:  TrianglePrior,
+			title: "Triangle",
+			hypos: prob.Triangle(prob.NewBound(0, 100)),
+		})
 	}
-	return obs
+	if len(priors) == 0 {
+		return nil, fmt.Errorf("prior family [%s] is not supported by the Euro problem", cfg.Prior)
+	}
+	return priors, nil
 }
 
-// runEuro runs the Euro problem for a given set of hypotheses and observations,
+// runEuro runs the Euro problem for a given set of hypotheses and counts of observations,
 // where a hypothesis represents that the probability of a heads is x%
-func runEuro(hypos []*prob.PmfElement, obs []prob.SuiteObservation) {
+func runEuro(cfg *Config, section string, hypos []*prob.PmfElement, counts map[prob.SuiteObservation]int) (*Result, error) {
 	s := prob.NewSuite(hypos...)
-	s.UpdateSet(obs)
-	report(s)
+	if err := s.UpdateCounts(counts); err != nil {
+		return nil, fmt.Errorf("unable to update suite: %v", err)
+	}
+	return euroResult(cfg, section, s)
 }
 
 type euroMultiObservation struct {
@@ -55,75 +82,155 @@ type euroMultiObservation struct {
 	nTails int64
 }
 
-// Getlikelihood is the likelihood function for the Euro problem using euroMultiObservation
+// Getlikelihood is the likelihood function for the Euro problem using euroMultiObservation: the
+// binomial probability of nHeads in nHeads+nTails flips, computed in log space so that it does not
+// underflow for many flips
 func (o *euroMultiObservation) GetLikelihood(hypo float64) float64 {
 	pHeads := hypo / 100
-	return math.Pow(pHeads, float64(o.nHeads)) * math.Pow(1-pHeads, float64(o.nTails))
+	if (pHeads == 0 && o.nHeads > 0) || (pHeads == 1 && o.nTails > 0) {
+		return 0
+	}
+	nHeads, nTails := float64(o.nHeads), float64(o.nTails)
+	lnChoose := lgamma(nHeads+nTails+1) - lgamma(nHeads+1) - lgamma(nTails+1)
+	// 0*log(0) is taken to be 0 so that a coin that always lands on one side can explain the flips
+	lnLike := lnChoose
+	if o.nHeads > 0 {
+		lnLike += nHeads * math.Log(pHeads)
+	}
+	if o.nTails > 0 {
+		lnLike += nTails * math.Log(1-pHeads)
+	}
+	return math.Exp(lnLike)
+}
+
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
 }
 
-func runEuroMultiObservation(hypos []*prob.PmfElement, ob *euroMultiObservation) {
+func runEuroMultiObservation(cfg *Config, section string, hypos []*prob.PmfElement, ob *euroMultiObservation) (*Result, error) {
 	s := prob.NewSuite(hypos...)
 	s.Update(ob)
-	report(s)
+	return euroResult(cfg, section, s)
 }
 
-func report(s *prob.Suite) {
-	mle, err := s.MaximumLikelihood()
+// euroResult returns the posterior and its summary statistics
+func euroResult(cfg *Config, section string, s *prob.Suite) (*Result, error) {
+	summary, err := summarize(s, cfg.CILength)
 	if err != nil {
-		fmt.Printf("Unable to compute maximum likelihood due to error [%v]", err)
-		return
+		return nil, err
 	}
-	fmt.Printf("Posterior maximum likelihood estimate: %0.2f\n", mle)
+	return &Result{Section: section, Posterior: pmfPosterior(s.Pmf), Summary: summary}, nil
+}
 
-	mean, err := s.Mean()
+// euroModelComparison evaluates whether the data support the hypothesis that the coin is biased
+// by comparing the evidence for a fair coin against that for biased coins under each prior
+func euroModelComparison(priors []*euroPrior, ob *euroMultiObservation) ([]*Result, error) {
+	section := "Model comparison"
+	m := prob.NewModelSet()
+	err := m.Add("fair", prob.NewSuite(prob.NewPmfElement(50, 1)), 1)
 	if err != nil {
-		fmt.Printf("Unable to compute mean due to error [%v]", err)
-		return
+		return nil, fmt.Errorf("unable to add model: %v", err)
 	}
-	fmt.Printf("Posterior mean: %0.2f\n", mean)
-
-	median, err := s.Percentile(0.5)
-	if err != nil {
-		fmt.Printf("Unable to compute median due to error [%v]", err)
-		return
+	for _, prior := range priors {
+		err := m.Add(prior.name, prob.NewSuite(prior.hypos...), 1)
+		if err != nil {
+			return nil, fmt.Errorf("unable to add model: %v", err)
+		}
+	}
+	m.Update(ob)
+
+	results := []*Result{}
+	for _, prior := range priors {
+		bf, err := m.BayesFactor(prior.name, "fair")
+		if err != nil {
+			return nil, fmt.Errorf("unable to compute Bayes factor: %v", err)
+		}
+		results = append(results, &Result{
+			Section: section,
+			Label:   fmt.Sprintf("%s vs fair", prior.name),
+			Values:  map[string]float64{"bayesFactor": bf},
+		})
 	}
-	fmt.Printf("Posterior median: %0.2f\n", median)
 
-	ci := 90.0
-	lower, upper, err := s.CredibleInterval(ci)
+	post, err := m.Posterior()
 	if err != nil {
-		fmt.Printf("Unable to compute %0.2f%%-CI due to error [%v]", ci, err)
-		return
+		return nil, fmt.Errorf("unable to compute posterior model probabilities: %v", err)
 	}
-	fmt.Printf("%0.2f%%-CI: (%0.2f, %0.2f)\n", ci, lower, upper)
+	results = append(results, &Result{
+		Section:   section,
+		Label:     "models",
+		Posterior: namedPmfPosterior(post),
+	})
+	return results, nil
 }
 
 // Euro runs the Euro problem
-func Euro() {
-
-	var nHeads int64 = 140
-	var nTails int64 = 110
-
-	uniformPrior := prob.Uniform(prob.NewBound(0, 100))
-	trianglePrior := prob.Triangle(prob.NewBound(0, 100))
-
-	// run Euro problem using an observation for each flip
-	obs := generateObs(nHeads, nTails)
-	fmt.Println("Uniform Prior:")
-	runEuro(uniformPrior, obs)
-	fmt.Println("Triangle Prior:")
-	runEuro(trianglePrior, obs)
+func Euro(cfg *Config) ([]*Result, error) {
+	priors, err := euroPriors(cfg)
+	if err != nil {
+		return nil, err
+	}
+	results := []*Result{}
+
+	// run Euro problem using the number of times each side was observed
+	counts := euroCounts(cfg.EuroHeads, cfg.EuroTails)
+	for _, prior := range priors {
+		r, err := runEuro(cfg, fmt.Sprintf("%s Prior", prior.title), prior.hypos, counts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
 
 	// run Euro problem using a multiobservationn to capture the results of multiple flips
-	ob := &euroMultiObservation{nHeads: nHeads, nTails: nTails}
-	fmt.Println("Uniform Prior (multiObservation):")
-	runEuroMultiObservation(uniformPrior, ob)
-	fmt.Println("Triangle Prior (multiObservation):")
-	runEuroMultiObservation(trianglePrior, ob)
+	ob := &euroMultiObservation{nHeads: cfg.EuroHeads, nTails: cfg.EuroTails}
+	for _, prior := range priors {
+		r, err := runEuroMultiObservation(cfg, fmt.Sprintf("%s Prior (multiObservation)", prior.title), prior.hypos, ob)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+
+	// compare a fair coin against biased coins with each prior
+	comparison, err := euroModelComparison(priors, ob)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, comparison...)
 
 	// run Euro problem using a (continuous) Beta prior
-	fmt.Println("Beta distribuion:")
 	b, _ := prob.NewBeta(1, 1) // ignore error since we are passing positive parameters
-	b.Update(float64(nHeads), float64(nTails))
-	fmt.Printf("Posterior mean: %0.2f\n", b.Mean())
+	b.Update(float64(cfg.EuroHeads), float64(cfg.EuroTails))
+	results = append(results, &Result{
+		Section: "Beta distribution",
+		Values:  map[string]float64{"mean": b.Mean()},
+	})
+	return results, nil
+}
+
+// writeEuroText writes the summary statistics of each posterior, the Bayes factors and posterior
+// probabilities of the compared models, and the posterior mean of the Beta distribution
+func writeEuroText(w io.Writer, results []*Result) {
+	writeSections(w, results, "%s:\n", func(w io.Writer, r *Result) {
+		if s := r.Summary; s != nil {
+			fmt.Fprintf(w, "Posterior maximum likelihood estimate: %0.2f\n", s.MAP)
+			fmt.Fprintf(w, "Posterior mean: %0.2f\n", s.Mean)
+			fmt.Fprintf(w, "Posterior median: %0.2f\n", s.Median)
+			fmt.Fprintf(w, "%0.2f%%-CI: (%0.2f, %0.2f)\n", s.CILength, s.CILower, s.CIUpper)
+			return
+		}
+		if bf, ok := r.Values["bayesFactor"]; ok {
+			fmt.Fprintf(w, "Bayes factor (%s): %0.2f (%s)\n", r.Label, bf, prob.JeffreysScale(bf))
+			return
+		}
+		if mean, ok := r.Values["mean"]; ok {
+			fmt.Fprintf(w, "Posterior mean: %0.2f\n", mean)
+			return
+		}
+		for _, p := range r.Posterior {
+			fmt.Fprintf(w, "P(%s): %0.2f\n", p.Hypothesis, p.Prob)
+		}
+	})
 }
@@ -0,0 +1,178 @@
+package exercises
+
+import (
+	"fmt"
+	"slices"
+)
+
+// prior families that can be selected for the exercises supporting them
+const (
+	UniformPrior  = "uniform"
+	TrianglePrior = "triangle"
+	PowerLawPrior = "powerlaw"
+)
+
+// Config contains the parameters of the exercises
+type Config struct {
+	LocomotiveBounds []int   // upper bounds of the priors of the locomotive problem
+	EuroHeads        int64   // number of heads observed in the Euro problem
+	EuroTails        int64   // number of tails observed in the Euro problem
+	Prior            string  // prior family of the exercises supporting it; empty runs each exercise's default families
+	CILength         float64 // length of reported credible intervals
+}
+
+// DefaultConfig returns the parameters of the exercises as posed in ThinkBayes
+func DefaultConfig() *Config {
+	return &Config{
+		LocomotiveBounds: []int{500, 1000, 2000},
+		EuroHeads:        140,
+		EuroTails:        110,
+		CILength:         90,
+	}
+}
+
+// Validate checks that the parameters are valid
+func (c *Config) Validate() error {
+	if len(c.LocomotiveBounds) == 0 {
+		return fmt.Errorf("at least one locomotive upper bound is required")
+	}
+	for _, bound := range c.LocomotiveBounds {
+		if bound < 1 {
+			return fmt.Errorf("locomotive upper bound [%d] must be positive", bound)
+		}
+	}
+	if c.EuroHeads < 0 || c.EuroTails < 0 {
+		return fmt.Errorf("euro head and tail counts [%d, %d] must be non-negative", c.EuroHeads, c.EuroTails)
+	}
+	if c.Prior != "" && !slices.Contains([]string{UniformPrior, TrianglePrior, PowerLawPrior}, c.Prior) {
+		return fmt.Errorf("unknown prior family [%s]", c.Prior)
+	}
+	if c.CILength <= 0 || c.CILength > 100 {
+		return fmt.Errorf("credible interval length [%v] must be in (0, 100]", c.CILength)
+	}
+	return nil
+}
+
+// usesPrior reports whether the prior family is selected by the config
+func (c *Config) usesPrior(name string) bool {
+	return c.Prior == "" || c.Prior == name
+}
+
+// Exercise is a named exercise that can be run with a Config
+type Exercise struct {
+	Name        string
+	Title       string
+	Description string
+	Priors      []string // prior families selectable with Config.Prior; nil if the exercise has no prior flag
+
+	run  func(cfg *Config) ([]*Result, error)
+	text textWriter
+}
+
+// SupportsPrior reports whether the exercise can be run with the prior family selected by Config.Prior
+func (e *Exercise) SupportsPrior(prior string) bool {
+	return prior == "" || e.Priors == nil || slices.Contains(e.Priors, prior)
+}
+
+// Run runs the exercise and returns a report of its results
+func (e *Exercise) Run(cfg *Config) (*Report, error) {
+	results, err := e.run(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{
+		Exercise: e.Name,
+		Title:    e.Title,
+		Results:  results,
+		text:     e.text,
+	}, nil
+}
+
+// exercises are listed in the order of the chapters of ThinkBayes
+var exercises = []*Exercise{
+	{
+		Name:        "cookie-manual",
+		Title:       "Cookie (manual calculation)",
+		Description: "cookie problem computed by multiplying priors by likelihoods",
+		run: func(*Config) ([]*Result, error) {
+			return CookieManual(), nil
+		},
+		text: writePosteriors("%0.2f"),
+	},
+	{
+		Name:        "cookie",
+		Title:       "Cookie",
+		Description: "cookie problem with many observations using a suite",
+		run: func(*Config) ([]*Result, error) {
+			return Cookie(), nil
+		},
+		text: writePosteriors("%0.2f"),
+	},
+	{
+		Name:        "monty-hall",
+		Title:       "Monty Hall",
+		Description: "Monty Hall problem",
+		run: func(*Config) ([]*Result, error) {
+			return MontyHall(), nil
+		},
+		text: writePosteriors("%0.2f"),
+	},
+	{
+		Name:        "mms",
+		Title:       "M&Ms",
+		Description: "M&M problem",
+		run: func(*Config) ([]*Result, error) {
+			return MMs(), nil
+		},
+		text: writePosteriors("%0.2f"),
+	},
+	{
+		Name:        "dice",
+		Title:       "Dice",
+		Description: "dice problem",
+		run: func(*Config) ([]*Result, error) {
+			return Dice(), nil
+		},
+		text: writePosteriors("%f"),
+	},
+	{
+		Name:        "dice-casino",
+		Title:       "Dice Casino",
+		Description: "dice casino problem using a hidden Markov model",
+		run: func(*Config) ([]*Result, error) {
+			return DiceCasino()
+		},
+		text: writeDiceCasinoText,
+	},
+	{
+		Name:        "locomotive",
+		Title:       "Locomotive",
+		Description: "locomotive problem (flags: bounds, prior, ci)",
+		Priors:      []string{UniformPrior, TrianglePrior, PowerLawPrior},
+		run:         Locomotive,
+		text:        writeLocomotiveText,
+	},
+	{
+		Name:        "euro",
+		Title:       "Euro",
+		Description: "Euro problem (flags: heads, tails, prior, ci)",
+		Priors:      []string{UniformPrior, TrianglePrior},
+		run:         Euro,
+		text:        writeEuroText,
+	},
+}
+
+// All returns all exercises
+func All() []*Exercise {
+	return append([]*Exercise{}, exercises...)
+}
+
+// Get returns the exercise with the specified name
+func Get(name string) (*Exercise, error) {
+	for _, e := range exercises {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown exercise [%s]", name)
+}
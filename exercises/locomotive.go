@@ -2,6 +2,8 @@ package exercises
 
 import (
 	"fmt"
+	"io"
+	"slices"
 
 	"github.com/dkaslovsky/GoThinkBayes/prob"
 )
@@ -11,12 +13,6 @@ import (
 // One day you see a locomotive with the number 60.
 // Estimate how many loco- motives the railroad has.
 
-var bounds = []*prob.Bound{
-	prob.NewBound(1, 500),
-	prob.NewBound(1, 1000),
-	prob.NewBound(1, 2000),
-}
-
 // locomotive observations (likelihood function) are the same as that of the dice problem
 type locomotiveObservation struct {
 	*diceObservation
@@ -28,112 +24,152 @@ func newLocomotiveObservation(val int) *locomotiveObservation {
 	}
 }
 
-func locomototiveUniformSingleObservation() {
-	fmt.Println("Uniform Single Observation")
-
-	for _, bound := range bounds {
-		hypos := prob.Uniform(bound)
-		s := prob.NewSuite(hypos...)
-		s.Update(newLocomotiveObservation(60))
-
-		mean, err := s.Mean()
-		if err != nil {
-			fmt.Printf("Unable to compute mean due to error [%v]", err)
-			continue
-		}
-		fmt.Printf("Upper bound: %d, Posterior mean: %0.2f\n", bound.High, mean)
-	}
+// locomotivePrior is a family of priors parameterized by upper bound
+type locomotivePrior struct {
+	name       string
+	title      string
+	family     prob.PriorFamily
+	multipleCI bool // print the credible interval for multiple observations
+	censored   bool // run the censored observation
 }
 
-func locomototiveUniformMultipleObservations() {
-	fmt.Println("Uniform Multiple Observation")
+var locomotivePriors = []*locomotivePrior{
+	{
+		name:     UniformPrior,
+		title:    "Uniform",
+		family:   prob.UpperBoundFamily(1, prob.Uniform),
+		censored: true,
+	},
+	{
+		name:   TrianglePrior,
+		title:  "Triangle",
+		family: prob.UpperBoundFamily(1, prob.Triangle),
+	},
+	{
+		name:       PowerLawPrior,
+		title:      "Power Law",
+		family:     prob.UpperBoundFamily(1, locomotivePowerLaw),
+		multipleCI: true,
+	},
+}
 
-	for _, bound := range bounds {
-		hypos := prob.Uniform(bound)
-		s := prob.NewSuite(hypos...)
+// locomotiveDefaultPriors are the prior families run when none is selected
+var locomotiveDefaultPriors = []string{UniformPrior, PowerLawPrior}
 
-		obs := []prob.SuiteObservation{
-			newLocomotiveObservation(60),
-			newLocomotiveObservation(30),
-			newLocomotiveObservation(90),
-		}
-		s.UpdateSet(obs)
+func locomotivePowerLaw(b *prob.Bound) []*prob.PmfElement {
+	alpha := 1.0
+	return prob.PowerLaw(b, alpha)
+}
 
-		mean, err := s.Mean()
-		if err != nil {
-			fmt.Printf("Unable to compute mean due to error [%v]", err)
-			continue
-		}
-		fmt.Printf("Upper bound: %d, Posterior mean: %0.2f\n", bound.High, mean)
+func locomotiveObservations(vals ...int) []prob.SuiteObservation {
+	obs := []prob.SuiteObservation{}
+	for _, val := range vals {
+		obs = append(obs, newLocomotiveObservation(val))
 	}
+	return obs
 }
 
-func locomotivePowerLawSingleObservation() {
-	fmt.Println("Power Law Single Observation")
-
-	alpha := 1.0
-	for _, bound := range bounds {
-		hypos := prob.PowerLaw(bound, alpha)
-		s := prob.NewSuite(hypos...)
-		s.Update(newLocomotiveObservation(60))
+// locomotiveSensitivity summarizes the posterior for each upper bound of a family of priors
+func locomotiveSensitivity(cfg *Config, section string, family prob.PriorFamily, obs []prob.SuiteObservation, printCI bool) ([]*Result, error) {
+	bounds := make([]float64, 0, len(cfg.LocomotiveBounds))
+	for _, bound := range cfg.LocomotiveBounds {
+		bounds = append(bounds, float64(bound))
+	}
 
-		mean, err := s.Mean()
-		if err != nil {
-			fmt.Printf("Unable to compute mean due to error [%v]", err)
-			continue
-		}
-		fmt.Printf("Upper bound: %d, Posterior mean: %0.2f\n", bound.High, mean)
+	table, err := prob.Sensitivity(family, bounds, obs, cfg.CILength)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute posterior summaries: %v", err)
 	}
+	results := []*Result{}
+	for _, row := range table {
+		results = append(results, &Result{
+			Section: section,
+			Label:   fmt.Sprintf("upper bound %0.0f", row.Param),
+			Summary: &Summary{
+				Mean:     row.Mean,
+				MAP:      row.MAP,
+				Median:   row.Median,
+				CILength: cfg.CILength,
+				CILower:  row.CILower,
+				CIUpper:  row.CIUpper,
+			},
+			Values: map[string]float64{"upperBound": row.Param},
+			omitCI: !printCI,
+		})
+	}
+	return results, nil
 }
 
-func locomotivePowerLawMultipleObservation() {
-	fmt.Println("Power Law Multiple Observation")
+// runLocomotivePrior runs the locomotive problem for a family of priors with a single observation
+// and with multiple observations
+func runLocomotivePrior(cfg *Config, prior *locomotivePrior) ([]*Result, error) {
+	section := fmt.Sprintf("%s Single Observation", prior.title)
+	single, err := locomotiveSensitivity(cfg, section, prior.family, locomotiveObservations(60), false)
+	if err != nil {
+		return nil, err
+	}
 
-	alpha := 1.0
-	for _, bound := range bounds {
-		hypos := prob.PowerLaw(bound, alpha)
-		s := prob.NewSuite(hypos...)
-
-		obs := []prob.SuiteObservation{
-			newLocomotiveObservation(60),
-			newLocomotiveObservation(30),
-			newLocomotiveObservation(90),
-		}
-		s.UpdateSet(obs)
+	section = fmt.Sprintf("%s Multiple Observation", prior.title)
+	multiple, err := locomotiveSensitivity(cfg, section, prior.family, locomotiveObservations(60, 30, 90), prior.multipleCI)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(single, multiple), nil
+}
 
-		mean, err := s.Mean()
-		if err != nil {
-			fmt.Printf("Unable to compute mean due to error [%v]", err)
-			continue
+// runLocomotiveCensored runs the locomotive problem for a family of priors with the censored
+// observation that a locomotive numbered at least 60 was seen
+func runLocomotiveCensored(cfg *Config, prior *locomotivePrior) ([]*Result, error) {
+	section := fmt.Sprintf("%s Censored Observation (at least 60)", prior.title)
+	obs := []prob.SuiteObservation{prob.RightCensored(59, prob.UniformUpToCdf)}
+	return locomotiveSensitivity(cfg, section, prior.family, obs, true)
+}
+
+// Locomotive runs the locomotive problem
+func Locomotive(cfg *Config) ([]*Result, error) {
+	names := locomotiveDefaultPriors
+	if cfg.Prior != "" {
+		names = []string{cfg.Prior}
+	}
+	priors := []*locomotivePrior{}
+	for _, name := range names {
+		for _, prior := range locomotivePriors {
+			if prior.name == name {
+				priors = append(priors, prior)
+			}
 		}
-		fmt.Printf("Upper bound: %d, Posterior mean: %0.2f ", bound.High, mean)
+	}
 
-		// compute 90% credible interval from cdf
-		cdf, err := s.MakeCdf()
+	results := []*Result{}
+	for _, prior := range priors {
+		priorResults, err := runLocomotivePrior(cfg, prior)
 		if err != nil {
-			fmt.Printf("[Could not compute 90%% Credible Interval due to error [%v]]\n", err)
-			continue
+			return nil, err
 		}
-		lower, err := cdf.Percentile(0.05)
-		if err != nil {
-			fmt.Printf("[Could not compute 90%% Credible Interval due to error [%v]]\n", err)
+		results = append(results, priorResults...)
+	}
+	for _, prior := range priors {
+		if !prior.censored {
 			continue
 		}
-		upper, err := cdf.Percentile(0.95)
+		censored, err := runLocomotiveCensored(cfg, prior)
 		if err != nil {
-			fmt.Printf("[Could not compute 90%% Credible Interval due to error [%v]]\n", err)
-			continue
+			return nil, err
 		}
-		fmt.Printf("[90%% Credible Interval: (%0.2f, %0.2f)]\n", lower, upper)
+		results = append(results, censored...)
 	}
-
+	return results, nil
 }
 
-// Locomotive runs the locomotive problem
-func Locomotive() {
-	locomototiveUniformSingleObservation()
-	locomototiveUniformMultipleObservations()
-	locomotivePowerLawSingleObservation()
-	locomotivePowerLawMultipleObservation()
-	fmt.Println()
+// writeLocomotiveText writes the posterior mean, and credible interval if not omitted, for each
+// upper bound
+func writeLocomotiveText(w io.Writer, results []*Result) {
+	writeSections(w, results, "%s\n", func(w io.Writer, r *Result) {
+		fmt.Fprintf(w, "Upper bound: %0.0f, Posterior mean: %0.2f", r.Values["upperBound"], r.Summary.Mean)
+		if !r.omitCI {
+			fmt.Fprintf(w, " [%v%% Credible Interval: (%0.2f, %0.2f)]", r.Summary.CILength, r.Summary.CILower, r.Summary.CIUpper)
+		}
+		fmt.Fprintln(w)
+	})
+	fmt.Fprintln(w)
 }
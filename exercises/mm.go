@@ -86,7 +86,7 @@ func (o *mmObservation) GetLikelihood(hypoName string) float64 {
 }
 
 // MMs runs the M&M problem
-func MMs() {
+func MMs() []*Result {
 	s := prob.NewNamedSuite(hypoA.hypo, hypoB.hypo)
 
 	obs := []prob.NamedSuiteObservation{
@@ -94,6 +94,5 @@ func MMs() {
 		&mmObservation{bag: "bag 2", color: "green"},
 	}
 	s.UpdateSet(obs)
-
-	s.Print()
+	return []*Result{{Section: "posterior", Posterior: namedPmfPosterior(s.NamedPmf)}}
 }
@@ -36,11 +36,10 @@ func (o *doorObservation) GetLikelihood(hypoName string) float64 {
 }
 
 // MontyHall runs the Monty Hall problem
-func MontyHall() {
+func MontyHall() []*Result {
 	s := prob.NewNamedSuite(doorA, doorB, doorC)
 
 	ob := &doorObservation{name: "door B"}
 	s.Update(ob)
-
-	s.Print()
+	return []*Result{{Section: "posterior", Posterior: namedPmfPosterior(s.NamedPmf)}}
 }
@@ -0,0 +1,208 @@
+package exercises
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/dkaslovsky/GoThinkBayes/prob"
+)
+
+// output formats of exercise reports
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+	CSVFormat  = "csv"
+)
+
+// Formats are the supported output formats of exercise reports
+var Formats = []string{TextFormat, JSONFormat, CSVFormat}
+
+// Probability is the posterior probability of a hypothesis
+type Probability struct {
+	Hypothesis string  `json:"hypothesis"`
+	Prob       float64 `json:"prob"`
+}
+
+// Summary contains summary statistics of a posterior distribution
+type Summary struct {
+	Mean     float64 `json:"mean"`
+	MAP      float64 `json:"map"`
+	Median   float64 `json:"median"`
+	CILength float64 `json:"ciLength"`
+	CILower  float64 `json:"ciLower"`
+	CIUpper  float64 `json:"ciUpper"`
+}
+
+// Result is a posterior distribution, its summary statistics and other named quantities computed
+// by a section of an exercise; fields not computed by the section are omitted
+type Result struct {
+	Section   string             `json:"section"`
+	Label     string             `json:"label,omitempty"`
+	Posterior []*Probability     `json:"posterior,omitempty"`
+	Summary   *Summary           `json:"summary,omitempty"`
+	Values    map[string]float64 `json:"values,omitempty"`
+
+	// omitCI omits the credible interval of the summary from the text output
+	omitCI bool
+}
+
+// prob returns the posterior probability of a hypothesis
+func (r *Result) prob(hypo string) float64 {
+	for _, p := range r.Posterior {
+		if p.Hypothesis == hypo {
+			return p.Prob
+		}
+	}
+	return 0
+}
+
+// textWriter writes the results of an exercise as text
+type textWriter func(w io.Writer, results []*Result)
+
+// Report contains the results of running an exercise
+type Report struct {
+	Exercise string    `json:"exercise"`
+	Title    string    `json:"title"`
+	Results  []*Result `json:"results"`
+
+	text textWriter
+}
+
+// Write writes the reports in the specified format
+func Write(w io.Writer, format string, reports []*Report) error {
+	switch format {
+	case TextFormat:
+		return writeText(w, reports)
+	case JSONFormat:
+		return writeJSON(w, reports)
+	case CSVFormat:
+		return writeCSV(w, reports)
+	default:
+		return fmt.Errorf("unknown format [%s]", format)
+	}
+}
+
+func writeText(w io.Writer, reports []*Report) error {
+	for _, r := range reports {
+		fmt.Fprintf(w, "%s:\n", r.Title)
+		r.text(w, r.Results)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, reports []*Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
+
+// writeCSV writes one row for each posterior probability, summary statistic and value of each result
+func writeCSV(w io.Writer, reports []*Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"exercise", "section", "label", "quantity", "hypothesis", "value"})
+	for _, report := range reports {
+		for _, r := range report.Results {
+			row := func(quantity string, hypo string, val float64) {
+				cw.Write([]string{
+					report.Exercise, r.Section, r.Label, quantity, hypo, strconv.FormatFloat(val, 'g', -1, 64),
+				})
+			}
+			for _, p := range r.Posterior {
+				row("posterior", p.Hypothesis, p.Prob)
+			}
+			if s := r.Summary; s != nil {
+				row("mean", "", s.Mean)
+				row("map", "", s.MAP)
+				row("median", "", s.Median)
+				row("ciLength", "", s.CILength)
+				row("ciLower", "", s.CILower)
+				row("ciUpper", "", s.CIUpper)
+			}
+			for _, name := range slices.Sorted(maps.Keys(r.Values)) {
+				row(name, "", r.Values[name])
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// pmfPosterior returns the probabilities of a Pmf
+func pmfPosterior(p *prob.Pmf) []*Probability {
+	posterior := []*Probability{}
+	for val, pr := range p.All() {
+		posterior = append(posterior, &Probability{Hypothesis: fmt.Sprintf("%v", val), Prob: pr})
+	}
+	return posterior
+}
+
+// namedPmfPosterior returns the probabilities of a NamedPmf
+func namedPmfPosterior(p *prob.NamedPmf) []*Probability {
+	posterior := []*Probability{}
+	for name, pr := range p.All() {
+		posterior = append(posterior, &Probability{Hypothesis: name, Prob: pr})
+	}
+	return posterior
+}
+
+// summarize computes summary statistics of a posterior using a credible interval of specified length
+func summarize(s *prob.Suite, ciLength float64) (*Summary, error) {
+	mle, err := s.MaximumLikelihood()
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute maximum likelihood: %v", err)
+	}
+	mean, err := s.Mean()
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute mean: %v", err)
+	}
+	median, err := s.Percentile(0.5)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute median: %v", err)
+	}
+	lower, upper, err := s.CredibleInterval(ciLength)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute %0.2f%%-CI: %v", ciLength, err)
+	}
+	return &Summary{
+		Mean:     mean,
+		MAP:      mle,
+		Median:   median,
+		CILength: ciLength,
+		CILower:  lower,
+		CIUpper:  upper,
+	}, nil
+}
+
+// writePosteriors returns a textWriter printing the posterior of each result using the format of
+// its probabilities
+func writePosteriors(probFormat string) textWriter {
+	return func(w io.Writer, results []*Result) {
+		border := "----------"
+		for _, r := range results {
+			fmt.Fprintln(w, border)
+			for _, p := range r.Posterior {
+				fmt.Fprintf(w, "%s: "+probFormat+"\n", p.Hypothesis, p.Prob)
+			}
+			fmt.Fprintln(w, border)
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// writeSections writes a header using headerFormat each time the section changes followed by the
+// text of each result
+func writeSections(w io.Writer, results []*Result, headerFormat string, write func(io.Writer, *Result)) {
+	section := ""
+	for i, r := range results {
+		if i == 0 || r.Section != section {
+			fmt.Fprintf(w, headerFormat, r.Section)
+			section = r.Section
+		}
+		write(w, r)
+	}
+}
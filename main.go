@@ -1,30 +1,9 @@
 package main
 
 import (
-	"fmt"
-
-	"github.com/dkaslovsky/GoThinkBayes/exercises"
+	"os"
 )
 
 func main() {
-	fmt.Println("Cookie (manual calculation):")
-	exercises.CookieManual()
-
-	fmt.Println("Cookie:")
-	exercises.Cookie()
-
-	fmt.Println("Monty Hall:")
-	exercises.MontyHall()
-
-	fmt.Println("M&Ms:")
-	exercises.MMs()
-
-	fmt.Println("Dice:")
-	exercises.Dice()
-
-	fmt.Println("Locomotive:")
-	exercises.Locomotive()
-
-	fmt.Println("Euro:")
-	exercises.Euro()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
 }
@@ -0,0 +1,256 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// defaultAttemptsPerSample is the number of simulations per requested sample allowed by default
+// before approximate Bayesian computation gives up
+const defaultAttemptsPerSample = 1000
+
+// Simulator simulates data under a hypothesis and returns its summary statistics
+type Simulator func(hypo float64, rng *rand.Rand) []float64
+
+// Distance computes the distance between observed and simulated summary statistics
+type Distance func(observed []float64, simulated []float64) float64
+
+// EuclideanDistance is the Euclidean distance between summary statistics
+func EuclideanDistance(observed []float64, simulated []float64) float64 {
+	if len(observed) != len(simulated) {
+		return math.Inf(1)
+	}
+	sum := 0.0
+	for i := range observed {
+		d := observed[i] - simulated[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// ABCConfig contains the settings of approximate Bayesian computation by rejection sampling
+type ABCConfig struct {
+	NSamples    int     // number of accepted samples
+	Tolerance   float64 // maximum distance at which a simulation is accepted
+	MaxAttempts int     // maximum number of simulations; 0 allows 1000 per sample
+	Seed        int64   // seed for random number generation; 0 seeds from the current time
+}
+
+// ABCSMCConfig contains the settings of approximate Bayesian computation by sequential Monte Carlo
+type ABCSMCConfig struct {
+	NParticles  int       // number of particles in each generation
+	Tolerances  []float64 // decreasing maximum distance at which a simulation is accepted in each generation
+	KernelWidth int       // particles are perturbed by up to KernelWidth positions in the support of the prior
+	MaxAttempts int       // maximum number of simulations in each generation; 0 allows 1000 per particle
+	Seed        int64     // seed for random number generation; 0 seeds from the current time
+}
+
+// ABCRejection approximates the posterior of a prior given observed summary statistics by drawing
+// hypotheses from the prior and accepting those whose simulated summary statistics are within
+// tolerance of the observed summary statistics
+func ABCRejection(
+	prior *Pmf,
+	simulate Simulator,
+	distance Distance,
+	observed []float64,
+	cfg *ABCConfig,
+) (*Pmf, error) {
+	if err := validateABCInputs(prior, simulate, distance); err != nil {
+		return nil, fmt.Errorf("unable to run ABC: %v", err)
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("unable to run ABC: config is required")
+	}
+	if cfg.NSamples <= 0 {
+		return nil, fmt.Errorf("unable to run ABC: number of samples [%d] must be positive", cfg.NSamples)
+	}
+	if cfg.Tolerance < 0 {
+		return nil, fmt.Errorf("unable to run ABC: tolerance [%v] must be non-negative", cfg.Tolerance)
+	}
+	rng := newRand(cfg.Seed)
+	maxAttempts := attemptsOrDefault(cfg.MaxAttempts, cfg.NSamples)
+
+	counts := map[float64]float64{}
+	accepted := 0
+	for attempt := 0; accepted < cfg.NSamples; attempt++ {
+		if attempt == maxAttempts {
+			return nil, fmt.Errorf(
+				"unable to run ABC: accepted %d of %d samples in %d attempts", accepted, cfg.NSamples, maxAttempts,
+			)
+		}
+		hypo, err := prior.Random(rng)
+		if err != nil {
+			return nil, fmt.Errorf("unable to run ABC: %v", err)
+		}
+		if distance(observed, simulate(hypo, rng)) <= cfg.Tolerance {
+			counts[hypo]++
+			accepted++
+		}
+	}
+
+	posterior := newPmfFromMap(counts)
+	posterior.Normalize()
+	return posterior, nil
+}
+
+// ABCSMC approximates the posterior of a prior given observed summary statistics using a sequence
+// of populations of weighted particles accepted at decreasing tolerances, where each population is
+// drawn by perturbing particles of the previous population
+func ABCSMC(
+	prior *Pmf,
+	simulate Simulator,
+	distance Distance,
+	observed []float64,
+	cfg *ABCSMCConfig,
+) (*Pmf, error) {
+	if err := validateABCInputs(prior, simulate, distance); err != nil {
+		return nil, fmt.Errorf("unable to run ABC-SMC: %v", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("unable to run ABC-SMC: %v", err)
+	}
+	rng := newRand(cfg.Seed)
+	maxAttempts := attemptsOrDefault(cfg.MaxAttempts, cfg.NParticles)
+
+	// particles are indices into the support of the prior so that they can be perturbed
+	support := prior.Values()
+	priorProbs := make([]float64, len(support))
+	for i, val := range support {
+		priorProbs[i] = prior.Prob(val)
+	}
+
+	particles := make([]int, 0, cfg.NParticles)
+	weights := make([]float64, 0, cfg.NParticles)
+	for gen, tol := range cfg.Tolerances {
+		prevParticles, prevWeights := particles, weights
+		prevPop := newPmfFromMap(indexWeights(prevParticles, prevWeights))
+		particles = make([]int, 0, cfg.NParticles)
+		weights = make([]float64, 0, cfg.NParticles)
+
+		for attempt := 0; len(particles) < cfg.NParticles; attempt++ {
+			if attempt == maxAttempts {
+				return nil, fmt.Errorf(
+					"unable to run ABC-SMC: accepted %d of %d particles in %d attempts in generation %d",
+					len(particles), cfg.NParticles, maxAttempts, gen,
+				)
+			}
+
+			var idx int
+			if gen == 0 {
+				hypo, err := prior.Random(rng)
+				if err != nil {
+					return nil, fmt.Errorf("unable to run ABC-SMC: %v", err)
+				}
+				idx, _ = prior.index(hypo)
+			} else {
+				prev, err := prevPop.Random(rng)
+				if err != nil {
+					return nil, fmt.Errorf("unable to run ABC-SMC: %v", err)
+				}
+				idx = int(prev) + rng.Intn(2*cfg.KernelWidth+1) - cfg.KernelWidth
+				if idx < 0 || idx >= len(support) || priorProbs[idx] == 0 {
+					continue
+				}
+			}
+
+			if distance(observed, simulate(support[idx], rng)) > tol {
+				continue
+			}
+
+			weight := 1.0
+			if gen > 0 {
+				// importance weight is the prior over the probability of proposing the particle
+				// from the previous population
+				proposal := 0.0
+				for j, prev := range prevParticles {
+					if abs(idx-prev) <= cfg.KernelWidth {
+						proposal += prevWeights[j]
+					}
+				}
+				weight = priorProbs[idx] / proposal
+			}
+			particles = append(particles, idx)
+			weights = append(weights, weight)
+		}
+		normalizeWeights(weights)
+	}
+
+	prob := map[float64]float64{}
+	for i, idx := range particles {
+		prob[support[idx]] += weights[i]
+	}
+	posterior := newPmfFromMap(prob)
+	posterior.Normalize()
+	return posterior, nil
+}
+
+func (c *ABCSMCConfig) validate() error {
+	if c == nil {
+		return fmt.Errorf("config is required")
+	}
+	if c.NParticles <= 0 {
+		return fmt.Errorf("number of particles [%d] must be positive", c.NParticles)
+	}
+	if len(c.Tolerances) == 0 {
+		return fmt.Errorf("tolerances are required")
+	}
+	for i, tol := range c.Tolerances {
+		if tol < 0 {
+			return fmt.Errorf("tolerance [%v] must be non-negative", tol)
+		}
+		if i > 0 && tol > c.Tolerances[i-1] {
+			return fmt.Errorf("tolerances must be non-increasing")
+		}
+	}
+	if c.KernelWidth < 0 {
+		return fmt.Errorf("kernel width [%d] must be non-negative", c.KernelWidth)
+	}
+	return nil
+}
+
+func validateABCInputs(prior *Pmf, simulate Simulator, distance Distance) error {
+	if prior == nil || len(prior.vals) == 0 {
+		return fmt.Errorf("prior is required")
+	}
+	if simulate == nil {
+		return fmt.Errorf("simulator is required")
+	}
+	if distance == nil {
+		return fmt.Errorf("distance is required")
+	}
+	return nil
+}
+
+func attemptsOrDefault(maxAttempts int, n int) int {
+	if maxAttempts > 0 {
+		return maxAttempts
+	}
+	return n * defaultAttemptsPerSample
+}
+
+// indexWeights sums weights of particles by index
+func indexWeights(particles []int, weights []float64) map[float64]float64 {
+	m := map[float64]float64{}
+	for i, idx := range particles {
+		m[float64(idx)] += weights[i]
+	}
+	return m
+}
+
+func normalizeWeights(weights []float64) {
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	for i := range weights {
+		weights[i] /= sum
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,156 @@
+package prob
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coinSimulator simulates the number of heads in n flips of a coin with probability of heads hypo
+func coinSimulator(n int) Simulator {
+	return func(hypo float64, rng *rand.Rand) []float64 {
+		heads := 0.0
+		for i := 0; i < n; i++ {
+			if rng.Float64() < hypo {
+				heads++
+			}
+		}
+		return []float64{heads}
+	}
+}
+
+type coinObservation struct {
+	heads float64
+	tails float64
+}
+
+func (o *coinObservation) GetLikelihood(hypo float64) float64 {
+	return math.Pow(hypo, o.heads) * math.Pow(1-hypo, o.tails)
+}
+
+// coinPrior is a uniform prior on the probability of heads
+func coinPrior() *Pmf {
+	p := NewPmf()
+	for i := 0; i <= 10; i++ {
+		p.Set(NewPmfElement(float64(i)/10, 1))
+	}
+	p.Normalize()
+	return p
+}
+
+func coinPosterior(heads, tails float64) *Suite {
+	s := &Suite{Pmf: coinPrior()}
+	s.Update(&coinObservation{heads: heads, tails: tails})
+	return s
+}
+
+func TestEuclideanDistance(t *testing.T) {
+	tests := map[string]struct {
+		observed  []float64
+		simulated []float64
+		expected  float64
+	}{
+		"equal": {
+			observed:  []float64{1, 2},
+			simulated: []float64{1, 2},
+			expected:  0,
+		},
+		"different": {
+			observed:  []float64{0, 0},
+			simulated: []float64{3, 4},
+			expected:  5,
+		},
+		"different lengths": {
+			observed:  []float64{0},
+			simulated: []float64{0, 0},
+			expected:  math.Inf(1),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, EuclideanDistance(test.observed, test.simulated))
+		})
+	}
+}
+
+func TestABCRejection(t *testing.T) {
+	observed := []float64{14}
+	simulate := coinSimulator(20)
+
+	t.Run("invalid inputs", func(t *testing.T) {
+		cfg := &ABCConfig{NSamples: 10}
+		_, err := ABCRejection(NewPmf(), simulate, EuclideanDistance, observed, cfg)
+		require.NotNil(t, err)
+		_, err = ABCRejection(coinPrior(), nil, EuclideanDistance, observed, cfg)
+		require.NotNil(t, err)
+		_, err = ABCRejection(coinPrior(), simulate, nil, observed, cfg)
+		require.NotNil(t, err)
+		_, err = ABCRejection(coinPrior(), simulate, EuclideanDistance, observed, nil)
+		require.NotNil(t, err)
+		_, err = ABCRejection(coinPrior(), simulate, EuclideanDistance, observed, &ABCConfig{NSamples: 0})
+		require.NotNil(t, err)
+		_, err = ABCRejection(coinPrior(), simulate, EuclideanDistance, observed, &ABCConfig{NSamples: 1, Tolerance: -1})
+		require.NotNil(t, err)
+	})
+
+	t.Run("too many attempts", func(t *testing.T) {
+		cfg := &ABCConfig{NSamples: 10, Tolerance: 0, MaxAttempts: 5, Seed: 1}
+		_, err := ABCRejection(coinPrior(), simulate, EuclideanDistance, []float64{-1}, cfg)
+		require.NotNil(t, err)
+	})
+
+	t.Run("approximates exact posterior", func(t *testing.T) {
+		cfg := &ABCConfig{NSamples: 5000, Tolerance: 0, Seed: 1}
+
+		posterior, err := ABCRejection(coinPrior(), simulate, EuclideanDistance, observed, cfg)
+		require.Nil(t, err)
+
+		exact := coinPosterior(14, 6)
+		for val, prob := range exact.All() {
+			assert.InDelta(t, prob, posterior.Prob(val), 0.02)
+		}
+	})
+}
+
+func TestABCSMC(t *testing.T) {
+	observed := []float64{14}
+	simulate := coinSimulator(20)
+
+	t.Run("invalid config", func(t *testing.T) {
+		tests := map[string]*ABCSMCConfig{
+			"nil config":            nil,
+			"no particles":          {NParticles: 0, Tolerances: []float64{1}},
+			"no tolerances":         {NParticles: 10},
+			"negative tolerance":    {NParticles: 10, Tolerances: []float64{-1}},
+			"increasing tolerances": {NParticles: 10, Tolerances: []float64{1, 2}},
+			"negative kernel width": {NParticles: 10, Tolerances: []float64{1}, KernelWidth: -1},
+		}
+		for name, cfg := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := ABCSMC(coinPrior(), simulate, EuclideanDistance, observed, cfg)
+				require.NotNil(t, err)
+			})
+		}
+	})
+
+	t.Run("approximates exact posterior", func(t *testing.T) {
+		cfg := &ABCSMCConfig{
+			NParticles:  5000,
+			Tolerances:  []float64{4, 2, 0},
+			KernelWidth: 1,
+			Seed:        1,
+		}
+
+		posterior, err := ABCSMC(coinPrior(), simulate, EuclideanDistance, observed, cfg)
+		require.Nil(t, err)
+
+		exact := coinPosterior(14, 6)
+		for val, prob := range exact.All() {
+			assert.InDelta(t, prob, posterior.Prob(val), 0.03)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package prob
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// ObservationSimulator simulates observations under a hypothesis
+type ObservationSimulator func(hypo float64, rng *rand.Rand) []SuiteObservation
+
+// SuiteFactory creates a new Suite holding the prior
+type SuiteFactory func() *Suite
+
+// Statistic simulates a scalar summary statistic of data under a hypothesis
+type Statistic func(hypo float64, rng *rand.Rand) float64
+
+// CalibrationConfig contains the settings of simulation-based calibration
+type CalibrationConfig struct {
+	NSimulations int       // number of simulated data sets
+	CILengths    []float64 // lengths of the credible intervals whose coverage is reported
+	NRankBins    int       // number of bins of the rank histogram
+	Seed         int64     // seed for random number generation; 0 seeds from the current time
+}
+
+// Coverage is the fraction of simulations in which a credible interval contained the true hypothesis
+type Coverage struct {
+	CILength float64 `json:"ciLength"`
+	Coverage float64 `json:"coverage"`
+}
+
+// CalibrationResult contains the results of simulation-based calibration
+type CalibrationResult struct {
+	// Ranks are the posterior probabilities below each true hypothesis, with ties randomized,
+	// which are uniformly distributed on [0, 1] for a calibrated model
+	Ranks         []float64   `json:"ranks"`
+	RankHistogram []int       `json:"rankHistogram"`
+	Coverage      []*Coverage `json:"coverage"`
+}
+
+// Calibrate runs simulation-based calibration by repeatedly drawing a true hypothesis from the prior,
+// simulating observations under it, updating a Suite created by the factory, and recording the rank
+// of the true hypothesis in the posterior and whether it is covered by each credible interval
+func Calibrate(
+	prior *Pmf,
+	simulate ObservationSimulator,
+	factory SuiteFactory,
+	cfg *CalibrationConfig,
+) (*CalibrationResult, error) {
+	if prior == nil || simulate == nil || factory == nil {
+		return nil, fmt.Errorf("unable to calibrate: prior, simulator and suite factory are required")
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("unable to calibrate: config is required")
+	}
+	if cfg.NSimulations <= 0 {
+		return nil, fmt.Errorf("unable to calibrate: number of simulations [%d] must be positive", cfg.NSimulations)
+	}
+	if cfg.NRankBins <= 0 {
+		return nil, fmt.Errorf("unable to calibrate: number of rank bins [%d] must be positive", cfg.NRankBins)
+	}
+	rng := newRand(cfg.Seed)
+
+	result := &CalibrationResult{
+		Ranks:         make([]float64, 0, cfg.NSimulations),
+		RankHistogram: make([]int, cfg.NRankBins),
+		Coverage:      make([]*Coverage, 0, len(cfg.CILengths)),
+	}
+	covered := make([]int, len(cfg.CILengths))
+
+	for sim := 0; sim < cfg.NSimulations; sim++ {
+		truth, err := prior.Random(rng)
+		if err != nil {
+			return nil, fmt.Errorf("unable to calibrate: %v", err)
+		}
+		s := factory()
+		for _, ob := range simulate(truth, rng) {
+			s.Update(ob)
+		}
+
+		rank, err := posteriorRank(s.Pmf, truth, rng)
+		if err != nil {
+			return nil, fmt.Errorf("unable to calibrate: simulation %d: %v", sim, err)
+		}
+		result.Ranks = append(result.Ranks, rank)
+		bin := min(int(rank*float64(cfg.NRankBins)), cfg.NRankBins-1)
+		result.RankHistogram[bin]++
+
+		for i, l := range cfg.CILengths {
+			lower, upper, err := s.CredibleInterval(l)
+			if err != nil {
+				return nil, fmt.Errorf("unable to calibrate: simulation %d: %v", sim, err)
+			}
+			if lower <= truth && truth <= upper {
+				covered[i]++
+			}
+		}
+	}
+
+	for i, l := range cfg.CILengths {
+		result.Coverage = append(result.Coverage, &Coverage{
+			CILength: l,
+			Coverage: float64(covered[i]) / float64(cfg.NSimulations),
+		})
+	}
+	return result, nil
+}
+
+// PosteriorPredictive returns the distribution of a statistic of replicated data, simulated under
+// hypotheses drawn from the posterior
+func PosteriorPredictive(posterior *Pmf, stat Statistic, nSamples int, seed int64) (*Pmf, error) {
+	if nSamples <= 0 {
+		return nil, fmt.Errorf("unable to simulate posterior predictive: number of samples [%d] must be positive", nSamples)
+	}
+	rng := newRand(seed)
+
+	counts := map[float64]float64{}
+	for i := 0; i < nSamples; i++ {
+		hypo, err := posterior.Random(rng)
+		if err != nil {
+			return nil, fmt.Errorf("unable to simulate posterior predictive: %v", err)
+		}
+		counts[stat(hypo, rng)]++
+	}
+
+	predictive := newPmfFromMap(counts)
+	predictive.Normalize()
+	return predictive, nil
+}
+
+// PredictivePValue returns the probability that the statistic of replicated data is at least as
+// large as the observed statistic; values near 0 or 1 indicate the model does not fit the data
+func PredictivePValue(predictive *Pmf, observed float64) float64 {
+	total, tail := 0.0, 0.0
+	for val, prob := range predictive.All() {
+		total += prob
+		if val >= observed {
+			tail += prob
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return tail / total
+}
+
+// posteriorRank returns the posterior probability below a value plus a uniformly random fraction
+// of the probability at the value, which is uniformly distributed if the value is drawn from the posterior
+func posteriorRank(p *Pmf, val float64, rng *rand.Rand) (float64, error) {
+	cumsum := p.cumulative()
+	if len(cumsum) == 0 || cumsum[len(cumsum)-1] == 0 {
+		return 0, fmt.Errorf("simulated observations have zero probability under the prior")
+	}
+	total := cumsum[len(cumsum)-1]
+
+	below := 0.0
+	i, ok := p.index(val)
+	if i > 0 {
+		below = cumsum[i-1]
+	}
+	at := 0.0
+	if ok {
+		at = p.probs[i]
+	}
+	return (below + rng.Float64()*at) / total, nil
+}
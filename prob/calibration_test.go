@@ -0,0 +1,134 @@
+package prob
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coinFlipsSimulator simulates n flips of a coin as a single observation of the number of heads and tails
+func coinFlipsSimulator(n int) ObservationSimulator {
+	simulate := coinSimulator(n)
+	return func(hypo float64, rng *rand.Rand) []SuiteObservation {
+		heads := simulate(hypo, rng)[0]
+		return []SuiteObservation{&coinObservation{heads: heads, tails: float64(n) - heads}}
+	}
+}
+
+func coinSuiteFactory() *Suite {
+	return &Suite{Pmf: coinPrior()}
+}
+
+// biasedCoinSuiteFactory creates a suite with a prior that disagrees with coinPrior
+func biasedCoinSuiteFactory() *Suite {
+	return NewSuite(NewPmfElement(0.8, 1), NewPmfElement(0.9, 1))
+}
+
+func TestCalibrateInvalidInputs(t *testing.T) {
+	simulate := coinFlipsSimulator(10)
+	cfg := &CalibrationConfig{NSimulations: 10, NRankBins: 2}
+
+	_, err := Calibrate(nil, simulate, coinSuiteFactory, cfg)
+	assert.NotNil(t, err)
+	_, err = Calibrate(coinPrior(), nil, coinSuiteFactory, cfg)
+	assert.NotNil(t, err)
+	_, err = Calibrate(coinPrior(), simulate, nil, cfg)
+	assert.NotNil(t, err)
+	_, err = Calibrate(coinPrior(), simulate, coinSuiteFactory, nil)
+	assert.NotNil(t, err)
+	_, err = Calibrate(coinPrior(), simulate, coinSuiteFactory, &CalibrationConfig{NSimulations: 0, NRankBins: 2})
+	assert.NotNil(t, err)
+	_, err = Calibrate(coinPrior(), simulate, coinSuiteFactory, &CalibrationConfig{NSimulations: 10, NRankBins: 0})
+	assert.NotNil(t, err)
+	_, err = Calibrate(NewPmf(), simulate, coinSuiteFactory, cfg)
+	assert.NotNil(t, err)
+	_, err = Calibrate(coinPrior(), simulate, coinSuiteFactory, &CalibrationConfig{
+		NSimulations: 10,
+		NRankBins:    2,
+		CILengths:    []float64{0},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestCalibrate(t *testing.T) {
+	cfg := &CalibrationConfig{
+		NSimulations: 2000,
+		CILengths:    []float64{50, 90},
+		NRankBins:    4,
+		Seed:         1,
+	}
+
+	t.Run("calibrated", func(t *testing.T) {
+		result, err := Calibrate(coinPrior(), coinFlipsSimulator(10), coinSuiteFactory, cfg)
+		require.Nil(t, err)
+		require.Len(t, result.Ranks, cfg.NSimulations)
+
+		total := 0
+		for _, count := range result.RankHistogram {
+			assert.InDelta(t, cfg.NSimulations/cfg.NRankBins, count, 100)
+			total += count
+		}
+		assert.Equal(t, cfg.NSimulations, total)
+
+		require.Len(t, result.Coverage, len(cfg.CILengths))
+		for i, c := range result.Coverage {
+			assert.Equal(t, cfg.CILengths[i], c.CILength)
+			// credible intervals of discrete distributions cover at least their nominal probability
+			assert.GreaterOrEqual(t, c.Coverage, c.CILength/100-0.05)
+		}
+	})
+
+	t.Run("miscalibrated", func(t *testing.T) {
+		result, err := Calibrate(coinPrior(), coinFlipsSimulator(10), biasedCoinSuiteFactory, cfg)
+		require.Nil(t, err)
+		assert.Less(t, result.Coverage[1].Coverage, 0.5)
+		// true hypotheses are mostly below the posterior
+		assert.Greater(t, result.RankHistogram[0], cfg.NSimulations/2)
+	})
+
+	t.Run("reproducible", func(t *testing.T) {
+		first, err := Calibrate(coinPrior(), coinFlipsSimulator(10), coinSuiteFactory, cfg)
+		require.Nil(t, err)
+		second, err := Calibrate(coinPrior(), coinFlipsSimulator(10), coinSuiteFactory, cfg)
+		require.Nil(t, err)
+		assert.Equal(t, first, second)
+	})
+}
+
+func TestPosteriorPredictive(t *testing.T) {
+	stat := func(hypo float64, rng *rand.Rand) float64 {
+		return coinSimulator(10)(hypo, rng)[0]
+	}
+
+	_, err := PosteriorPredictive(coinPrior(), stat, 0, 1)
+	assert.NotNil(t, err)
+	_, err = PosteriorPredictive(NewPmf(), stat, 10, 1)
+	assert.NotNil(t, err)
+
+	posterior := coinPosterior(14, 6)
+	predictive, err := PosteriorPredictive(posterior.Pmf, stat, 5000, 1)
+	require.Nil(t, err)
+	postMean, err := posterior.Mean()
+	require.Nil(t, err)
+	predMean, err := predictive.Mean()
+	require.Nil(t, err)
+	assert.InDelta(t, 10*postMean, predMean, 0.1)
+	assert.InDelta(t, 1, getSum(probMap(predictive)), float64EqualTol)
+
+	// observing 7 heads in 10 flips is consistent with the posterior, observing 0 is not
+	pValue := PredictivePValue(predictive, 7)
+	assert.Greater(t, pValue, 0.1)
+	assert.Less(t, pValue, 0.9)
+	assert.Greater(t, PredictivePValue(predictive, 0), 0.99)
+	assert.Equal(t, 0.0, PredictivePValue(predictive, 11))
+}
+
+func TestPredictivePValue(t *testing.T) {
+	p := newPmfFromMap(map[float64]float64{1: 1, 2: 2, 3: 1})
+	assert.Equal(t, 1.0, PredictivePValue(p, 1))
+	assert.Equal(t, 0.75, PredictivePValue(p, 2))
+	assert.Equal(t, 0.25, PredictivePValue(p, 2.5))
+	assert.Equal(t, 0.0, PredictivePValue(NewPmf(), 1))
+}
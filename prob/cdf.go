@@ -2,9 +2,14 @@ package prob
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 )
 
+// cdfTotalTol is the tolerance at which cumulative probabilities are considered to sum to 1
+const cdfTotalTol = 1e-9
+
 // Cdf is a cumulative distribution function
 type Cdf struct {
 	valToIdx map[float64]int
@@ -14,25 +19,35 @@ type Cdf struct {
 
 // NewCdf creates a new Cdf
 func NewCdf(p map[float64]float64) (c *Cdf, err error) {
-	if len(p) == 0 {
+	vals := sortKeys(p)
+	probs := make([]float64, 0, len(vals))
+	for _, val := range vals {
+		probs = append(probs, p[val])
+	}
+	return newCdfFromSorted(vals, probs)
+}
+
+// newCdfFromSorted creates a new Cdf from values in increasing order and their probabilities
+func newCdfFromSorted(vals []float64, probs []float64) (c *Cdf, err error) {
+	if len(vals) == 0 {
 		return c, fmt.Errorf("cannot compute cdf from empty input")
 	}
 
 	sum := 0.0
-	for _, prob := range p {
+	for _, prob := range probs {
 		sum += prob
 	}
 	if sum == 0 {
 		return c, fmt.Errorf("cannot compute cdf when all elements have probability 0")
 	}
 
-	valToIdx := map[float64]int{}
-	prob := []float64{}
+	valToIdx := make(map[float64]int, len(vals))
+	prob := make([]float64, 0, len(vals))
 	cumsum := 0.0
 
-	for i, val := range sortKeys(p) {
+	for i, val := range vals {
 		valToIdx[val] = i
-		cumsum += p[val] / sum
+		cumsum += probs[i] / sum
 		prob = append(prob, cumsum)
 	}
 
@@ -44,6 +59,15 @@ func NewCdf(p map[float64]float64) (c *Cdf, err error) {
 	return c, nil
 }
 
+// copy returns a copy of the Cdf
+func (c *Cdf) copy() *Cdf {
+	return &Cdf{
+		valToIdx: maps.Clone(c.valToIdx),
+		idxToVal: maps.Clone(c.idxToVal),
+		prob:     slices.Clone(c.prob),
+	}
+}
+
 // Percentile computes the specified percentile of the distribution
 func (c *Cdf) Percentile(p float64) (float64, error) {
 	if p < 0 || p > 1 {
@@ -52,9 +76,22 @@ func (c *Cdf) Percentile(p float64) (float64, error) {
 	i := sort.Search(len(c.prob), func(i int) bool {
 		return c.prob[i] >= p
 	})
+	// guard against rounding leaving the final cumulative probability just below 1
+	i = min(i, len(c.prob)-1)
 	return c.idxToVal[i], nil
 }
 
+// Prob returns the probability that a value drawn from the distribution is less than or equal to val
+func (c *Cdf) Prob(val float64) float64 {
+	i := sort.Search(len(c.prob), func(i int) bool {
+		return c.idxToVal[i] > val
+	})
+	if i == 0 {
+		return 0
+	}
+	return c.prob[i-1]
+}
+
 // CredibleInterval computes the lower and upper bounds of a credible interval of specified length
 func (c *Cdf) CredibleInterval(l float64) (float64, float64, error) {
 	return CredibleInterval(c, l)
@@ -38,6 +38,12 @@ func TestCdfPercentile(t *testing.T) {
 			expected:   4,
 			shouldErr:  false,
 		},
+		"percentile 1 with rounding": {
+			prob:       map[float64]float64{1: 1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1},
+			percentile: 1,
+			expected:   6,
+			shouldErr:  false,
+		},
 		"percentile 0.5": {
 			prob:       map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1},
 			percentile: 0.5,
@@ -69,6 +75,42 @@ func TestCdfPercentile(t *testing.T) {
 	}
 }
 
+func TestCdfProb(t *testing.T) {
+	tests := map[string]struct {
+		val      float64
+		expected float64
+	}{
+		"below minimum": {
+			val:      0,
+			expected: 0,
+		},
+		"minimum": {
+			val:      1,
+			expected: 0.2,
+		},
+		"between values": {
+			val:      2.5,
+			expected: 0.5,
+		},
+		"maximum": {
+			val:      4,
+			expected: 1,
+		},
+		"above maximum": {
+			val:      10,
+			expected: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewCdf(map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1})
+			require.Nil(t, err)
+			assert.InDelta(t, test.expected, c.Prob(test.val), float64EqualTol)
+		})
+	}
+}
+
 func TestSortKeys(t *testing.T) {
 	tests := map[string]struct {
 		input    map[float64]float64
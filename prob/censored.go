@@ -0,0 +1,105 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+)
+
+// ConditionalCdf returns the probability that a value is less than or equal to val under a hypothesis
+type ConditionalCdf func(val float64, hypo float64) float64
+
+// CdfFamily returns a ConditionalCdf evaluating the Cdf generated once for each of the hypotheses,
+// such as the values of a Suite; hypotheses not among them assign probability 0 to every value
+func CdfFamily(hypos []float64, f func(hypo float64) (*Cdf, error)) (ConditionalCdf, error) {
+	cdfs := make(map[float64]*Cdf, len(hypos))
+	for _, hypo := range hypos {
+		c, err := f(hypo)
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate cdf for hypothesis [%v]: %v", hypo, err)
+		}
+		cdfs[hypo] = c
+	}
+	return func(val float64, hypo float64) float64 {
+		c, ok := cdfs[hypo]
+		if !ok {
+			return 0
+		}
+		return c.Prob(val)
+	}, nil
+}
+
+// UniformUpToCdf is the ConditionalCdf of values distributed uniformly over 1, ..., hypo,
+// such as the number of a locomotive given the number of locomotives
+func UniformUpToCdf(val float64, hypo float64) float64 {
+	if hypo < 1 {
+		return 0
+	}
+	return math.Max(0, math.Min(math.Floor(val), hypo)) / hypo
+}
+
+// ExponentialCdf is the ConditionalCdf of values distributed exponentially with rate hypo,
+// such as survival times given a hazard rate
+func ExponentialCdf(val float64, hypo float64) float64 {
+	if val <= 0 {
+		return 0
+	}
+	return 1 - math.Exp(-hypo*val)
+}
+
+// LeftCensored returns the observation that a value is less than or equal to val
+func LeftCensored(val float64, cdf ConditionalCdf) SuiteObservation {
+	return LikelihoodFunc(func(hypo float64) float64 {
+		return cdf(val, hypo)
+	})
+}
+
+// RightCensored returns the observation that a value is greater than val, such as a survival time
+// beyond the end of follow-up; for integer values, "at least val" is RightCensored(val-1, cdf)
+func RightCensored(val float64, cdf ConditionalCdf) SuiteObservation {
+	return LikelihoodFunc(func(hypo float64) float64 {
+		return 1 - cdf(val, hypo)
+	})
+}
+
+// IntervalCensored returns the observation that a value is greater than low and less than or equal to high
+func IntervalCensored(low float64, high float64, cdf ConditionalCdf) (SuiteObservation, error) {
+	if low > high {
+		return nil, fmt.Errorf("lower bound [%v] of censoring interval exceeds upper bound [%v]", low, high)
+	}
+	return LikelihoodFunc(func(hypo float64) float64 {
+		return cdf(high, hypo) - cdf(low, hypo)
+	}), nil
+}
+
+// TruncatedObservation returns the observation of a value that could only have been observed if it
+// was greater than low and less than or equal to high, so that its likelihood is renormalized by the
+// probability of the interval under each hypothesis
+func TruncatedObservation(
+	val float64,
+	like ValueLikelihood,
+	cdf ConditionalCdf,
+	low float64,
+	high float64,
+) (SuiteObservation, error) {
+	if val <= low || val > high {
+		return nil, fmt.Errorf("value [%v] is outside of truncation interval (%v, %v]", val, low, high)
+	}
+	return LikelihoodFunc(func(hypo float64) float64 {
+		mass := cdf(high, hypo) - cdf(low, hypo)
+		if mass <= 0 {
+			return 0
+		}
+		return like(val, hypo) / mass
+	}), nil
+}
+
+// Truncate returns the elements with values within the bound
+func Truncate(elems []*PmfElement, b *Bound) []*PmfElement {
+	truncated := []*PmfElement{}
+	for _, elem := range elems {
+		if elem.Val >= float64(b.Low) && elem.Val <= float64(b.High) {
+			truncated = append(truncated, elem)
+		}
+	}
+	return truncated
+}
@@ -0,0 +1,161 @@
+package prob
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uniformUpToCdfs generates the Cdf of values distributed uniformly over 1, ..., hypo
+func uniformUpToCdfs(hypo float64) (*Cdf, error) {
+	p := map[float64]float64{}
+	for x := 1.0; x <= hypo; x++ {
+		p[x] = 1
+	}
+	return NewCdf(p)
+}
+
+func TestUniformUpToCdf(t *testing.T) {
+	tests := map[string]struct {
+		val      float64
+		hypo     float64
+		expected float64
+	}{
+		"below support": {
+			val:      0,
+			hypo:     4,
+			expected: 0,
+		},
+		"within support": {
+			val:      3,
+			hypo:     4,
+			expected: 0.75,
+		},
+		"between values": {
+			val:      3.5,
+			hypo:     4,
+			expected: 0.75,
+		},
+		"above support": {
+			val:      5,
+			hypo:     4,
+			expected: 1,
+		},
+		"invalid hypothesis": {
+			val:      1,
+			hypo:     0,
+			expected: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, UniformUpToCdf(test.val, test.hypo))
+		})
+	}
+}
+
+func TestCdfFamily(t *testing.T) {
+	calls := 0
+	countingCdfs := func(hypo float64) (*Cdf, error) {
+		calls++
+		return uniformUpToCdfs(hypo)
+	}
+
+	hypos := []float64{1, 3, 5}
+	cdf, err := CdfFamily(hypos, countingCdfs)
+	require.Nil(t, err)
+	for _, hypo := range hypos {
+		for _, val := range []float64{0, 1, 2.5, 3, 6} {
+			assert.InDelta(t, UniformUpToCdf(val, hypo), cdf(val, hypo), float64EqualTol)
+		}
+	}
+	// cdfs are generated once per hypothesis
+	assert.Equal(t, len(hypos), calls)
+	assert.Equal(t, 0.0, cdf(1, 4))
+
+	// no cdf can be generated without values
+	_, err = CdfFamily([]float64{0, 1}, uniformUpToCdfs)
+	assert.NotNil(t, err)
+}
+
+func TestExponentialCdf(t *testing.T) {
+	assert.Equal(t, 0.0, ExponentialCdf(-1, 2))
+	assert.Equal(t, 0.0, ExponentialCdf(0, 2))
+	assert.InDelta(t, 1-math.Exp(-1), ExponentialCdf(0.5, 2), float64EqualTol)
+}
+
+func TestCensored(t *testing.T) {
+	interval, err := IntervalCensored(1, 3, UniformUpToCdf)
+	require.Nil(t, err)
+	_, err = IntervalCensored(3, 1, UniformUpToCdf)
+	assert.NotNil(t, err)
+
+	tests := map[string]struct {
+		ob   SuiteObservation
+		pred func(float64) bool
+	}{
+		"left": {
+			ob:   LeftCensored(3, UniformUpToCdf),
+			pred: func(x float64) bool { return x <= 3 },
+		},
+		"right": {
+			ob:   RightCensored(3, UniformUpToCdf),
+			pred: func(x float64) bool { return x > 3 },
+		},
+		"interval": {
+			ob:   interval,
+			pred: func(x float64) bool { return x > 1 && x <= 3 },
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, hypo := range []float64{2, 3, 4, 5} {
+				// sum the probabilities of the values satisfying the censoring event
+				expected := 0.0
+				for x := 1.0; x <= hypo; x++ {
+					if test.pred(x) {
+						expected += 1 / hypo
+					}
+				}
+				assert.InDelta(t, expected, test.ob.GetLikelihood(hypo), float64EqualTol)
+			}
+		})
+	}
+}
+
+func TestRightCensoredUpdate(t *testing.T) {
+	// a locomotive numbered at least 3 rules out fewer than 3 locomotives
+	s := NewSuite(Uniform(NewBound(1, 5))...)
+	s.Update(RightCensored(2, UniformUpToCdf))
+
+	assert.Equal(t, 0.0, s.Prob(1))
+	assert.Equal(t, 0.0, s.Prob(2))
+	total := 1.0/3 + 2.0/4 + 3.0/5
+	assert.InDelta(t, (1.0/3)/total, s.Prob(3), float64EqualTol)
+	assert.InDelta(t, (3.0/5)/total, s.Prob(5), float64EqualTol)
+}
+
+func TestTruncatedObservation(t *testing.T) {
+	_, err := TruncatedObservation(1, uniformUpTo, UniformUpToCdf, 1, 4)
+	assert.NotNil(t, err)
+	_, err = TruncatedObservation(5, uniformUpTo, UniformUpToCdf, 1, 4)
+	assert.NotNil(t, err)
+
+	ob, err := TruncatedObservation(3, uniformUpTo, UniformUpToCdf, 1, 4)
+	require.Nil(t, err)
+	// values 2, ..., min(4, hypo) could have been observed
+	assert.Equal(t, 0.0, ob.GetLikelihood(1))
+	assert.Equal(t, 0.0, ob.GetLikelihood(2))
+	assert.InDelta(t, 0.5, ob.GetLikelihood(3), float64EqualTol)
+	assert.InDelta(t, 1.0/3, ob.GetLikelihood(6), float64EqualTol)
+}
+
+func TestTruncateElements(t *testing.T) {
+	elems := Truncate(Uniform(NewBound(1, 10)), NewBound(3, 5))
+	assert.Equal(t, Uniform(NewBound(3, 5)), elems)
+	assert.Empty(t, Truncate(Uniform(NewBound(1, 10)), NewBound(11, 12)))
+}
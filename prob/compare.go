@@ -0,0 +1,51 @@
+package prob
+
+// PmfProbLess returns the probability that a value drawn from a is less than
+// an independent value drawn from b
+func PmfProbLess(a, b *Pmf) float64 {
+	less, _, _ := Compare(a, b)
+	return less
+}
+
+// PmfProbGreater returns the probability that a value drawn from a is greater than
+// an independent value drawn from b
+func PmfProbGreater(a, b *Pmf) float64 {
+	_, _, greater := Compare(a, b)
+	return greater
+}
+
+// PmfProbEqual returns the probability that a value drawn from a is equal to
+// an independent value drawn from b
+func PmfProbEqual(a, b *Pmf) float64 {
+	_, equal, _ := Compare(a, b)
+	return equal
+}
+
+// Compare returns the probabilities that a value drawn from a is less than, equal to,
+// and greater than an independent value drawn from b
+func Compare(a, b *Pmf) (less float64, equal float64, greater float64) {
+	bTotal := 0.0
+	for _, prob := range b.probs {
+		bTotal += prob
+	}
+
+	// sweep the values of a in increasing order while accumulating the mass of b
+	// strictly below the current value, as in a cdf
+	j := 0
+	below := 0.0
+	for x, px := range a.All() {
+		for j < len(b.vals) && b.vals[j] < x {
+			below += b.probs[j]
+			j++
+		}
+		at := 0.0
+		if j < len(b.vals) && b.vals[j] == x {
+			at = b.probs[j]
+		}
+
+		greater += px * below
+		equal += px * at
+		less += px * (bTotal - below - at)
+	}
+	return less, equal, greater
+}
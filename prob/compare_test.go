@@ -0,0 +1,107 @@
+package prob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	tests := map[string]struct {
+		a               []*PmfElement
+		b               []*PmfElement
+		expectedLess    float64
+		expectedEqual   float64
+		expectedGreater float64
+	}{
+		"empty Pmfs": {
+			a:               []*PmfElement{},
+			b:               []*PmfElement{},
+			expectedLess:    0,
+			expectedEqual:   0,
+			expectedGreater: 0,
+		},
+		"identical point masses": {
+			a:               []*PmfElement{NewPmfElement(2, 1)},
+			b:               []*PmfElement{NewPmfElement(2, 1)},
+			expectedLess:    0,
+			expectedEqual:   1,
+			expectedGreater: 0,
+		},
+		"disjoint supports": {
+			a: []*PmfElement{
+				NewPmfElement(5, 0.5),
+				NewPmfElement(6, 0.5),
+			},
+			b: []*PmfElement{
+				NewPmfElement(1, 0.5),
+				NewPmfElement(2, 0.5),
+			},
+			expectedLess:    0,
+			expectedEqual:   0,
+			expectedGreater: 1,
+		},
+		"interleaved supports": {
+			a: []*PmfElement{
+				NewPmfElement(1, 0.2),
+				NewPmfElement(3, 0.5),
+				NewPmfElement(5, 0.3),
+			},
+			b: []*PmfElement{
+				NewPmfElement(2, 0.4),
+				NewPmfElement(3, 0.4),
+				NewPmfElement(6, 0.2),
+			},
+			// less:    0.2*1 + 0.5*0.2 + 0.3*0.2
+			// equal:   0.5*0.4
+			// greater: 0.5*0.4 + 0.3*0.8
+			expectedLess:    0.36,
+			expectedEqual:   0.2,
+			expectedGreater: 0.44,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := setupPmf(test.a)
+			b := setupPmf(test.b)
+
+			less, equal, greater := Compare(a, b)
+
+			assert.InDelta(t, test.expectedLess, less, float64EqualTol)
+			assert.InDelta(t, test.expectedEqual, equal, float64EqualTol)
+			assert.InDelta(t, test.expectedGreater, greater, float64EqualTol)
+
+			assert.Equal(t, less, PmfProbLess(a, b))
+			assert.Equal(t, equal, PmfProbEqual(a, b))
+			assert.Equal(t, greater, PmfProbGreater(a, b))
+		})
+	}
+}
+
+func TestCompareMatchesEnumeration(t *testing.T) {
+	t.Run("compare agrees with enumerating all pairs", func(t *testing.T) {
+		a := NewSuite(Triangle(NewBound(0, 20))...)
+		b := NewSuite(PowerLaw(NewBound(5, 30), 1)...)
+
+		expectedLess, expectedEqual, expectedGreater := 0.0, 0.0, 0.0
+		for x, px := range a.All() {
+			for y, py := range b.All() {
+				switch {
+				case x < y:
+					expectedLess += px * py
+				case x > y:
+					expectedGreater += px * py
+				default:
+					expectedEqual += px * py
+				}
+			}
+		}
+
+		less, equal, greater := Compare(a.Pmf, b.Pmf)
+
+		assert.InDelta(t, expectedLess, less, float64EqualTol)
+		assert.InDelta(t, expectedEqual, equal, float64EqualTol)
+		assert.InDelta(t, expectedGreater, greater, float64EqualTol)
+	})
+}
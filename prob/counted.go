@@ -0,0 +1,72 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+)
+
+// Counted is an observation repeated a number of times
+type Counted struct {
+	Ob    SuiteObservation
+	Count int
+}
+
+// NewCounted creates a new Counted
+func NewCounted(ob SuiteObservation, count int) *Counted {
+	return &Counted{
+		Ob:    ob,
+		Count: count,
+	}
+}
+
+// GetLikelihood is the likelihood of the observation raised to the number of times it was observed;
+// it can underflow for large counts, which Suite.UpdateCounted avoids by working in log space
+func (c *Counted) GetLikelihood(hypo float64) float64 {
+	return math.Pow(c.Ob.GetLikelihood(hypo), float64(c.Count))
+}
+
+// UpdateCounts updates the probabilities based on observations repeated the number of times given
+// by their counts; observations are used as map keys and so must be comparable (e.g., pointers)
+func (s *Suite) UpdateCounts(counts map[SuiteObservation]int) error {
+	obs := make([]*Counted, 0, len(counts))
+	for ob, count := range counts {
+		obs = append(obs, NewCounted(ob, count))
+	}
+	return s.UpdateCounted(obs...)
+}
+
+// UpdateCounted updates the probabilities based on repeated observations, evaluating the likelihood
+// of each distinct observation once per hypothesis and accumulating in log space for numerical
+// stability; the suite is left unchanged if the observations have zero probability under every hypothesis
+func (s *Suite) UpdateCounted(obs ...*Counted) error {
+	for _, ob := range obs {
+		if ob.Count < 0 {
+			return fmt.Errorf("unable to update suite: count [%d] must be non-negative", ob.Count)
+		}
+	}
+
+	logPost := make([]float64, len(s.vals))
+	maxLogPost := math.Inf(-1)
+	for i, hypo := range s.vals {
+		lp := math.Log(s.probs[i])
+		for _, ob := range obs {
+			if ob.Count == 0 || math.IsInf(lp, -1) {
+				continue
+			}
+			lp += float64(ob.Count) * math.Log(ob.Ob.GetLikelihood(hypo))
+		}
+		logPost[i] = lp
+		maxLogPost = math.Max(maxLogPost, lp)
+	}
+	if math.IsInf(maxLogPost, -1) {
+		return fmt.Errorf("unable to update suite: observations have zero probability under every hypothesis")
+	}
+
+	// subtract the maximum log posterior before exponentiating to avoid underflow
+	for i := range s.probs {
+		s.probs[i] = math.Exp(logPost[i] - maxLogPost)
+	}
+	s.invalidate()
+	s.Normalize()
+	return nil
+}
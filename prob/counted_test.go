@@ -0,0 +1,85 @@
+package prob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountedGetLikelihood(t *testing.T) {
+	ob := NewCounted(&suiteTestObservation{2}, 3)
+	assert.InDelta(t, 1.0/64, ob.GetLikelihood(4), float64EqualTol)
+	assert.Equal(t, 0.0, ob.GetLikelihood(1))
+	assert.Equal(t, 1.0, NewCounted(&suiteTestObservation{2}, 0).GetLikelihood(1))
+}
+
+func TestSuiteUpdateCounts(t *testing.T) {
+	ob2 := &suiteTestObservation{2}
+	ob3 := &suiteTestObservation{3}
+
+	tests := map[string]struct {
+		counts    map[SuiteObservation]int
+		obs       []SuiteObservation
+		shouldErr bool
+	}{
+		"empty": {
+			counts: map[SuiteObservation]int{},
+			obs:    []SuiteObservation{},
+		},
+		"zero count": {
+			counts: map[SuiteObservation]int{ob2: 2, &suiteTestObservation{6}: 0},
+			obs:    []SuiteObservation{ob2, ob2},
+		},
+		"repeated observations": {
+			counts: map[SuiteObservation]int{ob2: 4, ob3: 3},
+			obs:    []SuiteObservation{ob2, ob3, ob2, ob3, ob2, ob3, ob2},
+		},
+		"negative count": {
+			counts:    map[SuiteObservation]int{ob2: -1},
+			shouldErr: true,
+		},
+		"zero probability": {
+			counts:    map[SuiteObservation]int{&suiteTestObservation{6}: 1},
+			shouldErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewSuite(suiteUpdateHypos...)
+			prior := probMap(s)
+			err := s.UpdateCounts(test.counts)
+			if test.shouldErr {
+				assert.NotNil(t, err)
+				assert.Equal(t, prior, probMap(s))
+				return
+			}
+			require.Nil(t, err)
+
+			expected := NewSuite(suiteUpdateHypos...)
+			expected.UpdateSet(test.obs)
+			assert.InDeltaMapValues(t, probMap(expected), probMap(s), float64EqualTol)
+		})
+	}
+}
+
+func TestSuiteUpdateCountedLargeCounts(t *testing.T) {
+	// likelihoods raised to these counts underflow to 0 for every hypothesis
+	heads := &coinObservation{heads: 1}
+	tails := &coinObservation{tails: 1}
+	counted := []*Counted{NewCounted(heads, 6000), NewCounted(tails, 4000)}
+
+	underflow := &Suite{Pmf: coinPrior()}
+	for _, ob := range counted {
+		underflow.Update(ob)
+	}
+	assert.Equal(t, 0.0, getSum(probMap(underflow)))
+
+	s := &Suite{Pmf: coinPrior()}
+	require.Nil(t, s.UpdateCounted(counted...))
+	assert.InDelta(t, 1, getSum(probMap(s)), float64EqualTol)
+	mle, err := s.MaximumLikelihood()
+	require.Nil(t, err)
+	assert.Equal(t, 0.6, mle)
+}
@@ -0,0 +1,118 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Utility returns the utility of taking an action when the true value is val
+type Utility func(action float64, val float64) float64
+
+// Loss returns the loss incurred by taking an action when the true value is val
+type Loss func(action float64, val float64) float64
+
+// Utility converts a Loss to the equivalent Utility
+func (l Loss) Utility() Utility {
+	return func(action float64, val float64) float64 {
+		return -l(action, val)
+	}
+}
+
+// SquaredLoss is the squared error loss, whose Bayes-optimal estimate is the posterior mean
+func SquaredLoss(action float64, val float64) float64 {
+	return (action - val) * (action - val)
+}
+
+// AbsoluteLoss is the absolute error loss, whose Bayes-optimal estimate is the posterior median
+func AbsoluteLoss(action float64, val float64) float64 {
+	return math.Abs(action - val)
+}
+
+// ZeroOneLoss is the 0-1 loss, whose Bayes-optimal estimate is the posterior maximum likelihood value
+func ZeroOneLoss(action float64, val float64) float64 {
+	if action == val {
+		return 0
+	}
+	return 1
+}
+
+// ActionUtility is the expected utility of an action under a distribution
+type ActionUtility struct {
+	Action          float64 `json:"action"`
+	ExpectedUtility float64 `json:"expectedUtility"`
+}
+
+// ExpectedUtilities computes the expected utility of each action under a distribution
+func ExpectedUtilities(p *Pmf, actions []float64, u Utility) ([]*ActionUtility, error) {
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("unable to compute expected utility: no actions")
+	}
+	total := 0.0
+	for _, prob := range p.probs {
+		total += prob
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("unable to compute expected utility from empty pmf or all zero probabilities")
+	}
+
+	eus := make([]*ActionUtility, 0, len(actions))
+	for _, action := range actions {
+		eu := 0.0
+		for i, val := range p.vals {
+			if p.probs[i] == 0 {
+				continue
+			}
+			eu += p.probs[i] * u(action, val)
+		}
+		eus = append(eus, &ActionUtility{
+			Action:          action,
+			ExpectedUtility: eu / total,
+		})
+	}
+	return eus, nil
+}
+
+// OptimalAction returns the action with the highest expected utility under a distribution;
+// ties resolve to the earliest action
+func OptimalAction(p *Pmf, actions []float64, u Utility) (*ActionUtility, error) {
+	eus, err := ExpectedUtilities(p, actions, u)
+	if err != nil {
+		return nil, err
+	}
+	best := eus[0]
+	for _, eu := range eus[1:] {
+		if eu.ExpectedUtility > best.ExpectedUtility {
+			best = eu
+		}
+	}
+	return best, nil
+}
+
+// BayesEstimate returns the action minimizing the expected loss under a distribution, considering
+// the values of the distribution and its mean as the actions so that the estimate is the mean under
+// SquaredLoss, the median under AbsoluteLoss and the maximum likelihood value under ZeroOneLoss;
+// ties resolve to the smallest value of the distribution
+func BayesEstimate(p *Pmf, l Loss) (float64, error) {
+	mean, err := p.Mean()
+	if err != nil {
+		return 0, fmt.Errorf("unable to compute Bayes estimate: %v", err)
+	}
+	// normalize the mean as expected utilities are normalized
+	total := 0.0
+	for _, prob := range p.probs {
+		total += prob
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("unable to compute Bayes estimate from pmf with all zero probabilities")
+	}
+	mean /= total
+
+	// the mean follows the values so that it is chosen only if it is strictly better
+	actions := append(slices.Clone(p.vals), mean)
+	best, err := OptimalAction(p, actions, l.Utility())
+	if err != nil {
+		return 0, fmt.Errorf("unable to compute Bayes estimate: %v", err)
+	}
+	return best.Action, nil
+}
@@ -0,0 +1,146 @@
+package prob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDecisionPmf() *Pmf {
+	return newPmfFromMap(map[float64]float64{
+		1:  0.1,
+		2:  0.45,
+		3:  0.15,
+		10: 0.3,
+	})
+}
+
+// bidUtility wins the bid when it does not exceed the value
+func bidUtility(bid float64, val float64) float64 {
+	if bid > val {
+		return 0
+	}
+	return bid
+}
+
+func TestExpectedUtilities(t *testing.T) {
+	tests := map[string]struct {
+		pmf       *Pmf
+		actions   []float64
+		expected  []*ActionUtility
+		shouldErr bool
+	}{
+		"empty pmf": {
+			pmf:       NewPmf(),
+			actions:   []float64{1},
+			shouldErr: true,
+		},
+		"zero probabilities": {
+			pmf:       newPmfFromMap(map[float64]float64{1: 0}),
+			actions:   []float64{1},
+			shouldErr: true,
+		},
+		"no actions": {
+			pmf:       setupDecisionPmf(),
+			actions:   []float64{},
+			shouldErr: true,
+		},
+		"bids": {
+			pmf:     setupDecisionPmf(),
+			actions: []float64{1, 2, 3, 10, 11},
+			expected: []*ActionUtility{
+				{Action: 1, ExpectedUtility: 1},
+				{Action: 2, ExpectedUtility: 1.8},
+				{Action: 3, ExpectedUtility: 1.35},
+				{Action: 10, ExpectedUtility: 3},
+				{Action: 11, ExpectedUtility: 0},
+			},
+		},
+		"unnormalized": {
+			pmf:     newPmfFromMap(map[float64]float64{1: 1, 3: 3}),
+			actions: []float64{1, 3},
+			expected: []*ActionUtility{
+				{Action: 1, ExpectedUtility: 1},
+				{Action: 3, ExpectedUtility: 2.25},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			eus, err := ExpectedUtilities(test.pmf, test.actions, bidUtility)
+			if test.shouldErr {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Len(t, eus, len(test.expected))
+			for i, eu := range eus {
+				assert.Equal(t, test.expected[i].Action, eu.Action)
+				assert.InDelta(t, test.expected[i].ExpectedUtility, eu.ExpectedUtility, float64EqualTol)
+			}
+		})
+	}
+}
+
+func TestOptimalAction(t *testing.T) {
+	p := setupDecisionPmf()
+
+	best, err := OptimalAction(p, []float64{1, 2, 3, 10}, bidUtility)
+	require.Nil(t, err)
+	assert.Equal(t, 10.0, best.Action)
+	assert.InDelta(t, 3, best.ExpectedUtility, float64EqualTol)
+
+	// ties resolve to the earliest action
+	constant := func(float64, float64) float64 { return 1 }
+	best, err = OptimalAction(p, []float64{3, 1, 2}, constant)
+	require.Nil(t, err)
+	assert.Equal(t, 3.0, best.Action)
+
+	_, err = OptimalAction(p, nil, bidUtility)
+	assert.NotNil(t, err)
+}
+
+func TestLossEstimates(t *testing.T) {
+	p := setupDecisionPmf()
+
+	mean, err := p.Mean()
+	require.Nil(t, err)
+	estimate, err := BayesEstimate(p, SquaredLoss)
+	require.Nil(t, err)
+	assert.Equal(t, mean, estimate)
+
+	// the mean is optimal over a grid of actions containing it
+	best, err := OptimalAction(p, Linspace(1, 10, 181), Loss(SquaredLoss).Utility())
+	require.Nil(t, err)
+	assert.InDelta(t, mean, best.Action, float64EqualTol)
+
+	median, err := p.Percentile(0.5)
+	require.Nil(t, err)
+	estimate, err = BayesEstimate(p, AbsoluteLoss)
+	require.Nil(t, err)
+	assert.Equal(t, median, estimate)
+
+	mle, err := p.MaximumLikelihood()
+	require.Nil(t, err)
+	estimate, err = BayesEstimate(p, ZeroOneLoss)
+	require.Nil(t, err)
+	assert.Equal(t, mle, estimate)
+
+	_, err = BayesEstimate(NewPmf(), SquaredLoss)
+	assert.NotNil(t, err)
+	_, err = BayesEstimate(newPmfFromMap(map[float64]float64{1: 0}), SquaredLoss)
+	assert.NotNil(t, err)
+
+	// estimates of an unnormalized pmf are those of the normalized pmf
+	unnormalized := newPmfFromMap(map[float64]float64{
+		1:  0.4,
+		2:  1.8,
+		3:  0.6,
+		10: 1.2,
+	})
+	estimate, err = BayesEstimate(unnormalized, SquaredLoss)
+	require.Nil(t, err)
+	assert.InDelta(t, mean, estimate, float64EqualTol)
+}
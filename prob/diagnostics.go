@@ -0,0 +1,256 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+)
+
+// Sampler runs a single Markov chain from initial parameters, such as a closure over
+// MetropolisHastings or SliceSample
+type Sampler func(init []float64, cfg *SamplerConfig) (*Samples, error)
+
+// Chains contains the samples of multiple Markov chains run on the same posterior
+type Chains struct {
+	chains []*Samples
+}
+
+// RunChains runs a chain from each of the initial parameters; if cfg.Seed is nonzero, chain i
+// is seeded with cfg.Seed + i so that the chains are reproducible but distinct
+func RunChains(sampler Sampler, inits [][]float64, cfg *SamplerConfig) (*Chains, error) {
+	if len(inits) == 0 {
+		return nil, fmt.Errorf("unable to run chains: initial parameters are required")
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("unable to run chains: sampler config is required")
+	}
+
+	c := &Chains{}
+	for i, init := range inits {
+		chainCfg := *cfg
+		if cfg.Seed != 0 {
+			chainCfg.Seed = cfg.Seed + int64(i)
+		}
+		samples, err := sampler(init, &chainCfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to run chain %d: %v", i, err)
+		}
+		c.chains = append(c.chains, samples)
+	}
+	return c, nil
+}
+
+// Len returns the number of chains
+func (c *Chains) Len() int {
+	return len(c.chains)
+}
+
+// Chain returns the samples of the i-th chain
+func (c *Chains) Chain(i int) *Samples {
+	return c.chains[i]
+}
+
+// Pooled returns the samples of all chains combined
+func (c *Chains) Pooled() *Samples {
+	pooled := &Samples{}
+	for _, chain := range c.chains {
+		pooled.draws = append(pooled.draws, chain.draws...)
+		pooled.accepted += chain.accepted
+		pooled.proposed += chain.proposed
+	}
+	return pooled
+}
+
+// ParamDiagnostics contains summary statistics and convergence diagnostics of a parameter
+type ParamDiagnostics struct {
+	Param            int
+	Mean             float64
+	CILower          float64
+	CIUpper          float64
+	RHat             float64
+	EffectiveSamples float64
+}
+
+// Diagnose computes summary statistics of the pooled samples, including a credible interval of
+// specified length, along with R-hat and effective sample size for each parameter
+func (c *Chains) Diagnose(ciLength float64) ([]*ParamDiagnostics, error) {
+	if len(c.chains) < 2 {
+		return nil, fmt.Errorf("unable to diagnose chains: at least 2 chains are required")
+	}
+	pooled := c.Pooled()
+
+	diags := []*ParamDiagnostics{}
+	for idx := 0; idx < pooled.NParams(); idx++ {
+		chainVals := make([][]float64, 0, len(c.chains))
+		for _, chain := range c.chains {
+			vals, err := chain.Param(idx)
+			if err != nil {
+				return nil, fmt.Errorf("unable to diagnose parameter %d: %v", idx, err)
+			}
+			chainVals = append(chainVals, vals)
+		}
+
+		rHat, err := RHat(chainVals...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to diagnose parameter %d: %v", idx, err)
+		}
+		ess, err := EffectiveSampleSize(chainVals...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to diagnose parameter %d: %v", idx, err)
+		}
+
+		p, err := pooled.MakePmf(idx, 0)
+		if err != nil {
+			return nil, fmt.Errorf("unable to diagnose parameter %d: %v", idx, err)
+		}
+		mean, err := p.Mean()
+		if err != nil {
+			return nil, fmt.Errorf("unable to diagnose parameter %d: %v", idx, err)
+		}
+		lower, upper, err := p.CredibleInterval(ciLength)
+		if err != nil {
+			return nil, fmt.Errorf("unable to diagnose parameter %d: %v", idx, err)
+		}
+
+		diags = append(diags, &ParamDiagnostics{
+			Param:            idx,
+			Mean:             mean,
+			CILower:          lower,
+			CIUpper:          upper,
+			RHat:             rHat,
+			EffectiveSamples: ess,
+		})
+	}
+	return diags, nil
+}
+
+// Autocorrelation computes the autocorrelation of a sequence at lags 0 through maxLag
+func Autocorrelation(x []float64, maxLag int) ([]float64, error) {
+	n := len(x)
+	if n < 2 {
+		return nil, fmt.Errorf("cannot compute autocorrelation of fewer than 2 values")
+	}
+	if maxLag < 0 || maxLag >= n {
+		return nil, fmt.Errorf("lag [%d] is outside of required range [0, %d]", maxLag, n-1)
+	}
+
+	mean, variance := meanVariance(x)
+	if variance == 0 {
+		return nil, fmt.Errorf("cannot compute autocorrelation of constant values")
+	}
+
+	acf := make([]float64, maxLag+1)
+	for lag := 0; lag <= maxLag; lag++ {
+		sum := 0.0
+		for i := 0; i+lag < n; i++ {
+			sum += (x[i] - mean) * (x[i+lag] - mean)
+		}
+		// normalize by n (rather than n - lag) so that the estimate is positive semidefinite
+		acf[lag] = sum / float64(n) / variance
+	}
+	return acf, nil
+}
+
+// RHat computes the Gelman-Rubin potential scale reduction factor of multiple chains of equal
+// length; values near 1 indicate the chains have converged to a common distribution
+func RHat(chains ...[]float64) (float64, error) {
+	if len(chains) < 2 {
+		return 0, fmt.Errorf("cannot compute R-hat of fewer than 2 chains")
+	}
+	w, varPlus, err := chainVariances(chains)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compute R-hat: %v", err)
+	}
+	if w == 0 {
+		return 0, fmt.Errorf("cannot compute R-hat of chains with zero within-chain variance")
+	}
+	return math.Sqrt(varPlus / w), nil
+}
+
+// EffectiveSampleSize estimates the number of independent samples equivalent to one or more chains
+// of equal length, accounting for autocorrelation within and between chains
+func EffectiveSampleSize(chains ...[]float64) (float64, error) {
+	if len(chains) == 0 {
+		return 0, fmt.Errorf("cannot compute effective sample size of no chains")
+	}
+	_, varPlus, err := chainVariances(chains)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compute effective sample size: %v", err)
+	}
+	if varPlus == 0 {
+		return 0, fmt.Errorf("cannot compute effective sample size of constant values")
+	}
+	m := len(chains)
+	n := len(chains[0])
+
+	// rho computes the combined autocorrelation at a lag from the variogram
+	rho := func(lag int) float64 {
+		variogram := 0.0
+		for _, chain := range chains {
+			for i := lag; i < n; i++ {
+				d := chain[i] - chain[i-lag]
+				variogram += d * d
+			}
+		}
+		variogram /= float64(m * (n - lag))
+		return 1 - variogram/(2*varPlus)
+	}
+
+	// sum autocorrelations in pairs until a pair sum is negative (Geyer's initial positive sequence)
+	sum := 0.0
+	for lag := 1; lag+1 < n; lag += 2 {
+		pair := rho(lag) + rho(lag+1)
+		if pair < 0 {
+			break
+		}
+		sum += pair
+	}
+
+	total := float64(m * n)
+	ess := total / (1 + 2*sum)
+	return min(ess, total), nil
+}
+
+// chainVariances returns the mean within-chain variance and the pooled estimate of posterior variance
+func chainVariances(chains [][]float64) (w float64, varPlus float64, err error) {
+	m := len(chains)
+	n := len(chains[0])
+	if n < 2 {
+		return 0, 0, fmt.Errorf("chains must contain at least 2 samples")
+	}
+
+	means := make([]float64, 0, m)
+	for _, chain := range chains {
+		if len(chain) != n {
+			return 0, 0, fmt.Errorf("chains must be of equal length")
+		}
+		mean, variance := meanVariance(chain)
+		means = append(means, mean)
+		// use the unbiased within-chain variance
+		w += variance * float64(n) / float64(n-1)
+	}
+	w /= float64(m)
+
+	// between-chain variance
+	b := 0.0
+	if m > 1 {
+		_, meansVariance := meanVariance(means)
+		b = float64(n) * meansVariance * float64(m) / float64(m-1)
+	}
+
+	varPlus = float64(n-1)/float64(n)*w + b/float64(n)
+	return w, varPlus, nil
+}
+
+// meanVariance computes the mean and (biased) variance of values
+func meanVariance(x []float64) (mean float64, variance float64) {
+	for _, val := range x {
+		mean += val
+	}
+	mean /= float64(len(x))
+	for _, val := range x {
+		d := val - mean
+		variance += d * d
+	}
+	variance /= float64(len(x))
+	return mean, variance
+}
@@ -0,0 +1,181 @@
+package prob
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func normalSequence(seed int64, n int, mean float64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = mean + rng.NormFloat64()
+	}
+	return x
+}
+
+// ar1Sequence generates an autoregressive sequence with unit marginal variance
+func ar1Sequence(seed int64, n int, phi float64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	x := make([]float64, n)
+	scale := math.Sqrt(1 - phi*phi)
+	for i := 1; i < n; i++ {
+		x[i] = phi*x[i-1] + scale*rng.NormFloat64()
+	}
+	return x
+}
+
+func TestAutocorrelation(t *testing.T) {
+	tests := map[string]struct {
+		x         []float64
+		maxLag    int
+		expected  []float64
+		shouldErr bool
+	}{
+		"too few values": {
+			x:         []float64{1},
+			maxLag:    0,
+			shouldErr: true,
+		},
+		"negative lag": {
+			x:         []float64{1, 2},
+			maxLag:    -1,
+			shouldErr: true,
+		},
+		"lag too large": {
+			x:         []float64{1, 2},
+			maxLag:    2,
+			shouldErr: true,
+		},
+		"constant values": {
+			x:         []float64{1, 1, 1},
+			maxLag:    1,
+			shouldErr: true,
+		},
+		"alternating values": {
+			x:        []float64{1, -1, 1, -1},
+			maxLag:   2,
+			expected: []float64{1, -0.75, 0.5},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			acf, err := Autocorrelation(test.x, test.maxLag)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.InDeltaSlice(t, test.expected, acf, float64EqualTol)
+		})
+	}
+}
+
+func TestRHat(t *testing.T) {
+	t.Run("fewer than 2 chains", func(t *testing.T) {
+		_, err := RHat(normalSequence(1, 100, 0))
+		require.NotNil(t, err)
+	})
+
+	t.Run("chains of unequal length", func(t *testing.T) {
+		_, err := RHat(normalSequence(1, 100, 0), normalSequence(2, 50, 0))
+		require.NotNil(t, err)
+	})
+
+	t.Run("converged chains", func(t *testing.T) {
+		rHat, err := RHat(
+			normalSequence(1, 1000, 0),
+			normalSequence(2, 1000, 0),
+			normalSequence(3, 1000, 0),
+		)
+		require.Nil(t, err)
+		assert.InDelta(t, 1, rHat, 0.01)
+	})
+
+	t.Run("chains with different means", func(t *testing.T) {
+		rHat, err := RHat(
+			normalSequence(1, 1000, 0),
+			normalSequence(2, 1000, 5),
+		)
+		require.Nil(t, err)
+		assert.Greater(t, rHat, 2.0)
+	})
+}
+
+func TestEffectiveSampleSize(t *testing.T) {
+	t.Run("no chains", func(t *testing.T) {
+		_, err := EffectiveSampleSize()
+		require.NotNil(t, err)
+	})
+
+	t.Run("independent samples", func(t *testing.T) {
+		ess, err := EffectiveSampleSize(normalSequence(1, 2000, 0), normalSequence(2, 2000, 0))
+		require.Nil(t, err)
+		assert.InEpsilon(t, 4000, ess, 0.2)
+	})
+
+	t.Run("autocorrelated samples", func(t *testing.T) {
+		// the effective sample size of an AR(1) sequence is approximately n(1 - phi)/(1 + phi)
+		n := 20000
+		phi := 0.9
+		ess, err := EffectiveSampleSize(ar1Sequence(1, n, phi))
+		require.Nil(t, err)
+		assert.InEpsilon(t, float64(n)*(1-phi)/(1+phi), ess, 0.3)
+	})
+}
+
+func TestRunChains(t *testing.T) {
+	logPost := normalLogPosterior([]float64{1, -1}, []float64{1, 1})
+	sampler := func(init []float64, cfg *SamplerConfig) (*Samples, error) {
+		return MetropolisHastings(logPost, init, []float64{1, 1}, cfg)
+	}
+
+	t.Run("no initial parameters", func(t *testing.T) {
+		_, err := RunChains(sampler, [][]float64{}, &SamplerConfig{NSamples: 10})
+		require.NotNil(t, err)
+	})
+
+	t.Run("sampler error", func(t *testing.T) {
+		_, err := RunChains(sampler, [][]float64{{0}}, &SamplerConfig{NSamples: 10})
+		require.NotNil(t, err)
+	})
+
+	t.Run("diagnose converged chains", func(t *testing.T) {
+		cfg := &SamplerConfig{NSamples: 2000, BurnIn: 200, Seed: 5}
+		chains, err := RunChains(sampler, [][]float64{{-5, -5}, {0, 0}, {5, 5}}, cfg)
+		require.Nil(t, err)
+
+		require.Equal(t, 3, chains.Len())
+		assert.NotEqual(t, chains.Chain(0).draws, chains.Chain(1).draws)
+		assert.Equal(t, 6000, chains.Pooled().Len())
+
+		diags, err := chains.Diagnose(90)
+		require.Nil(t, err)
+		require.Len(t, diags, 2)
+
+		for i, expectedMean := range []float64{1, -1} {
+			diag := diags[i]
+			assert.Equal(t, i, diag.Param)
+			assert.InDelta(t, expectedMean, diag.Mean, 0.15)
+			assert.Less(t, diag.CILower, diag.Mean)
+			assert.Greater(t, diag.CIUpper, diag.Mean)
+			assert.InDelta(t, 1, diag.RHat, 0.05)
+			assert.Greater(t, diag.EffectiveSamples, 100.0)
+			assert.LessOrEqual(t, diag.EffectiveSamples, 6000.0)
+		}
+	})
+
+	t.Run("diagnose single chain", func(t *testing.T) {
+		chains, err := RunChains(sampler, [][]float64{{0, 0}}, &SamplerConfig{NSamples: 10, Seed: 1})
+		require.Nil(t, err)
+
+		_, err = chains.Diagnose(90)
+		require.NotNil(t, err)
+	})
+}
@@ -158,9 +158,9 @@ func TestBetaMakePmf(t *testing.T) {
 
 		p := b.MakePmf(nSteps)
 
-		assert.Equal(t, len(expectedElems), len(p.prob))
+		assert.Equal(t, len(expectedElems), len(probMap(p)))
 
-		for val, pr := range p.prob {
+		for val, pr := range probMap(p) {
 			require.Contains(t, expectedElems, val)
 			expectedPr := expectedElems[val]
 			if expectedPr == 0 {
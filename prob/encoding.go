@@ -0,0 +1,576 @@
+package prob
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// cdfElement is the serialized form of an element of a Cdf
+type cdfElement struct {
+	Val     float64 `json:"value"`
+	CumProb float64 `json:"cumProb"`
+}
+
+// namedCdfElement is the serialized form of an element of a NamedCdf
+type namedCdfElement struct {
+	Name    string  `json:"name"`
+	CumProb float64 `json:"cumProb"`
+}
+
+// namedPmfRecord is the serialized form of a NamedPmf
+type namedPmfRecord struct {
+	Elements []*NamedPmfElement `json:"elements"`
+	Order    []string           `json:"order,omitempty"`
+}
+
+var (
+	pmfCSVHeader      = []string{"value", "prob"}
+	namedPmfCSVHeader = []string{"name", "prob"}
+	cdfCSVHeader      = []string{"value", "cumprob"}
+	namedCdfCSVHeader = []string{"name", "cumprob"}
+)
+
+// MarshalJSON encodes the Pmf as a list of elements sorted by value
+func (p *Pmf) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Items())
+}
+
+// UnmarshalJSON decodes a Pmf from a list of elements
+func (p *Pmf) UnmarshalJSON(data []byte) error {
+	elems := []*PmfElement{}
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return fmt.Errorf("unable to decode pmf: %v", err)
+	}
+	return p.load(elems)
+}
+
+// MarshalBinary encodes the Pmf using gob
+func (p *Pmf) MarshalBinary() ([]byte, error) {
+	return gobEncode(p.Items())
+}
+
+// UnmarshalBinary decodes a Pmf encoded by MarshalBinary
+func (p *Pmf) UnmarshalBinary(data []byte) error {
+	elems := []*PmfElement{}
+	if err := gobDecode(data, &elems); err != nil {
+		return fmt.Errorf("unable to decode pmf: %v", err)
+	}
+	return p.load(elems)
+}
+
+// WriteCSV writes the Pmf as (value,prob) records sorted by value
+func (p *Pmf) WriteCSV(w io.Writer) error {
+	rows := [][]string{}
+	for val, prob := range p.All() {
+		rows = append(rows, []string{formatFloat(val), formatFloat(prob)})
+	}
+	return writeCSV(w, pmfCSVHeader, rows)
+}
+
+// ReadPmfCSV reads a Pmf from (value,prob) records
+func ReadPmfCSV(r io.Reader) (*Pmf, error) {
+	rows, err := readCSV(r, pmfCSVHeader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read pmf: %v", err)
+	}
+
+	elems := []*PmfElement{}
+	for i, row := range rows {
+		val, prob, err := parseFloatPair(row)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read pmf: record %d: %v", i+1, err)
+		}
+		elems = append(elems, NewPmfElement(val, prob))
+	}
+
+	p := NewPmf()
+	if err := p.load(elems); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Pmf) load(elems []*PmfElement) error {
+	prob := map[float64]float64{}
+	for _, elem := range elems {
+		if elem == nil {
+			return fmt.Errorf("invalid pmf: missing element")
+		}
+		if math.IsNaN(elem.Val) || math.IsInf(elem.Val, 0) {
+			return fmt.Errorf("invalid pmf: value [%v] is not finite", elem.Val)
+		}
+		if err := validateProb(elem.Prob); err != nil {
+			return fmt.Errorf("invalid pmf: value [%v]: %v", elem.Val, err)
+		}
+		if _, ok := prob[elem.Val]; ok {
+			return fmt.Errorf("invalid pmf: duplicate value [%v]", elem.Val)
+		}
+		prob[elem.Val] = elem.Prob
+	}
+	loaded := newPmfFromMap(prob)
+	p.vals, p.probs = loaded.vals, loaded.probs
+	p.invalidate()
+	return nil
+}
+
+// MarshalJSON encodes the NamedPmf as a list of elements in insertion order along with
+// the ordering set by SetOrder, if any
+func (p *NamedPmf) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.record())
+}
+
+// UnmarshalJSON decodes a NamedPmf encoded by MarshalJSON
+func (p *NamedPmf) UnmarshalJSON(data []byte) error {
+	rec := namedPmfRecord{}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return fmt.Errorf("unable to decode named pmf: %v", err)
+	}
+	return p.load(rec)
+}
+
+// MarshalBinary encodes the NamedPmf using gob
+func (p *NamedPmf) MarshalBinary() ([]byte, error) {
+	return gobEncode(p.record())
+}
+
+// UnmarshalBinary decodes a NamedPmf encoded by MarshalBinary
+func (p *NamedPmf) UnmarshalBinary(data []byte) error {
+	rec := namedPmfRecord{}
+	if err := gobDecode(data, &rec); err != nil {
+		return fmt.Errorf("unable to decode named pmf: %v", err)
+	}
+	return p.load(rec)
+}
+
+// WriteCSV writes the NamedPmf as (name,prob) records in insertion order;
+// the ordering set by SetOrder is not written
+func (p *NamedPmf) WriteCSV(w io.Writer) error {
+	rows := [][]string{}
+	for name, prob := range p.All() {
+		rows = append(rows, []string{name, formatFloat(prob)})
+	}
+	return writeCSV(w, namedPmfCSVHeader, rows)
+}
+
+// ReadNamedPmfCSV reads a NamedPmf from (name,prob) records, preserving their order
+func ReadNamedPmfCSV(r io.Reader) (*NamedPmf, error) {
+	rows, err := readCSV(r, namedPmfCSVHeader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read named pmf: %v", err)
+	}
+
+	rec := namedPmfRecord{}
+	for i, row := range rows {
+		prob, err := parseFloat(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("unable to read named pmf: record %d: %v", i+1, err)
+		}
+		rec.Elements = append(rec.Elements, NewNamedPmfElement(row[0], prob))
+	}
+
+	p := NewNamedPmf()
+	if err := p.load(rec); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *NamedPmf) record() namedPmfRecord {
+	return namedPmfRecord{
+		Elements: p.Items(),
+		Order:    p.order,
+	}
+}
+
+func (p *NamedPmf) load(rec namedPmfRecord) error {
+	loaded := NewNamedPmf()
+	for _, elem := range rec.Elements {
+		if elem == nil {
+			return fmt.Errorf("invalid named pmf: missing element")
+		}
+		if err := validateProb(elem.Prob); err != nil {
+			return fmt.Errorf("invalid named pmf: name [%s]: %v", elem.Name, err)
+		}
+		if _, ok := loaded.nameToIdx[elem.Name]; ok {
+			return fmt.Errorf("invalid named pmf: duplicate name [%s]", elem.Name)
+		}
+		loaded.Set(elem)
+	}
+
+	if rec.Order != nil {
+		ordered := map[string]bool{}
+		for _, name := range rec.Order {
+			ordered[name] = true
+		}
+		for name := range loaded.nameToIdx {
+			if !ordered[name] {
+				return fmt.Errorf("invalid named pmf: ordering does not include name [%s]", name)
+			}
+		}
+		loaded.SetOrder(rec.Order...)
+	}
+
+	*p = *loaded
+	return nil
+}
+
+// UnmarshalJSON decodes a Suite from a list of elements and normalizes it
+func (s *Suite) UnmarshalJSON(data []byte) error {
+	p := NewPmf()
+	if err := p.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	s.loadPmf(p)
+	return nil
+}
+
+// UnmarshalBinary decodes a Suite encoded by MarshalBinary and normalizes it
+func (s *Suite) UnmarshalBinary(data []byte) error {
+	p := NewPmf()
+	if err := p.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	s.loadPmf(p)
+	return nil
+}
+
+// ReadSuiteCSV reads a Suite from (value,prob) records and normalizes it
+func ReadSuiteCSV(r io.Reader) (*Suite, error) {
+	p, err := ReadPmfCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	s := &Suite{}
+	s.loadPmf(p)
+	return s, nil
+}
+
+func (s *Suite) loadPmf(p *Pmf) {
+	s.Pmf = p
+	s.Normalize()
+}
+
+// UnmarshalJSON decodes a NamedSuite encoded by MarshalJSON and normalizes it
+func (s *NamedSuite) UnmarshalJSON(data []byte) error {
+	p := NewNamedPmf()
+	if err := p.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	s.loadNamedPmf(p)
+	return nil
+}
+
+// UnmarshalBinary decodes a NamedSuite encoded by MarshalBinary and normalizes it
+func (s *NamedSuite) UnmarshalBinary(data []byte) error {
+	p := NewNamedPmf()
+	if err := p.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	s.loadNamedPmf(p)
+	return nil
+}
+
+// ReadNamedSuiteCSV reads a NamedSuite from (name,prob) records and normalizes it
+func ReadNamedSuiteCSV(r io.Reader) (*NamedSuite, error) {
+	p, err := ReadNamedPmfCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	s := &NamedSuite{}
+	s.loadNamedPmf(p)
+	return s, nil
+}
+
+func (s *NamedSuite) loadNamedPmf(p *NamedPmf) {
+	s.NamedPmf = p
+	s.Normalize()
+}
+
+// MarshalJSON encodes the Cdf as a list of values and cumulative probabilities
+func (c *Cdf) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.elements())
+}
+
+// UnmarshalJSON decodes a Cdf encoded by MarshalJSON
+func (c *Cdf) UnmarshalJSON(data []byte) error {
+	elems := []cdfElement{}
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return fmt.Errorf("unable to decode cdf: %v", err)
+	}
+	return c.load(elems)
+}
+
+// MarshalBinary encodes the Cdf using gob
+func (c *Cdf) MarshalBinary() ([]byte, error) {
+	return gobEncode(c.elements())
+}
+
+// UnmarshalBinary decodes a Cdf encoded by MarshalBinary
+func (c *Cdf) UnmarshalBinary(data []byte) error {
+	elems := []cdfElement{}
+	if err := gobDecode(data, &elems); err != nil {
+		return fmt.Errorf("unable to decode cdf: %v", err)
+	}
+	return c.load(elems)
+}
+
+// WriteCSV writes the Cdf as (value,cumprob) records
+func (c *Cdf) WriteCSV(w io.Writer) error {
+	rows := [][]string{}
+	for _, elem := range c.elements() {
+		rows = append(rows, []string{formatFloat(elem.Val), formatFloat(elem.CumProb)})
+	}
+	return writeCSV(w, cdfCSVHeader, rows)
+}
+
+// ReadCdfCSV reads a Cdf from (value,cumprob) records
+func ReadCdfCSV(r io.Reader) (*Cdf, error) {
+	rows, err := readCSV(r, cdfCSVHeader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cdf: %v", err)
+	}
+
+	elems := []cdfElement{}
+	for i, row := range rows {
+		val, cumProb, err := parseFloatPair(row)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read cdf: record %d: %v", i+1, err)
+		}
+		elems = append(elems, cdfElement{Val: val, CumProb: cumProb})
+	}
+
+	c := &Cdf{}
+	if err := c.load(elems); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Cdf) elements() []cdfElement {
+	elems := make([]cdfElement, 0, len(c.prob))
+	for i, cumProb := range c.prob {
+		elems = append(elems, cdfElement{Val: c.idxToVal[i], CumProb: cumProb})
+	}
+	return elems
+}
+
+func (c *Cdf) load(elems []cdfElement) error {
+	vals := make([]float64, 0, len(elems))
+	cumProbs := make([]float64, 0, len(elems))
+	for i, elem := range elems {
+		if math.IsNaN(elem.Val) || math.IsInf(elem.Val, 0) {
+			return fmt.Errorf("invalid cdf: value [%v] is not finite", elem.Val)
+		}
+		if i > 0 && elem.Val <= vals[i-1] {
+			return fmt.Errorf("invalid cdf: values are not strictly increasing at [%v]", elem.Val)
+		}
+		vals = append(vals, elem.Val)
+		cumProbs = append(cumProbs, elem.CumProb)
+	}
+	if err := validateCumProbs(cumProbs); err != nil {
+		return fmt.Errorf("invalid cdf: %v", err)
+	}
+
+	valToIdx := map[float64]int{}
+	for i, val := range vals {
+		valToIdx[val] = i
+	}
+	c.valToIdx = valToIdx
+	c.idxToVal = reverseMap(valToIdx)
+	c.prob = cumProbs
+	return nil
+}
+
+// MarshalJSON encodes the NamedCdf as a list of ordered names and cumulative probabilities
+func (c *NamedCdf) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.elements())
+}
+
+// UnmarshalJSON decodes a NamedCdf encoded by MarshalJSON
+func (c *NamedCdf) UnmarshalJSON(data []byte) error {
+	elems := []namedCdfElement{}
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return fmt.Errorf("unable to decode named cdf: %v", err)
+	}
+	return c.load(elems)
+}
+
+// MarshalBinary encodes the NamedCdf using gob
+func (c *NamedCdf) MarshalBinary() ([]byte, error) {
+	return gobEncode(c.elements())
+}
+
+// UnmarshalBinary decodes a NamedCdf encoded by MarshalBinary
+func (c *NamedCdf) UnmarshalBinary(data []byte) error {
+	elems := []namedCdfElement{}
+	if err := gobDecode(data, &elems); err != nil {
+		return fmt.Errorf("unable to decode named cdf: %v", err)
+	}
+	return c.load(elems)
+}
+
+// WriteCSV writes the NamedCdf as (name,cumprob) records in order
+func (c *NamedCdf) WriteCSV(w io.Writer) error {
+	rows := [][]string{}
+	for _, elem := range c.elements() {
+		rows = append(rows, []string{elem.Name, formatFloat(elem.CumProb)})
+	}
+	return writeCSV(w, namedCdfCSVHeader, rows)
+}
+
+// ReadNamedCdfCSV reads a NamedCdf from (name,cumprob) records
+func ReadNamedCdfCSV(r io.Reader) (*NamedCdf, error) {
+	rows, err := readCSV(r, namedCdfCSVHeader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read named cdf: %v", err)
+	}
+
+	elems := []namedCdfElement{}
+	for i, row := range rows {
+		cumProb, err := parseFloat(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("unable to read named cdf: record %d: %v", i+1, err)
+		}
+		elems = append(elems, namedCdfElement{Name: row[0], CumProb: cumProb})
+	}
+
+	c := &NamedCdf{}
+	if err := c.load(elems); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *NamedCdf) elements() []namedCdfElement {
+	cdfElems := c.cdf.elements()
+	elems := make([]namedCdfElement, 0, len(cdfElems))
+	for _, elem := range cdfElems {
+		elems = append(elems, namedCdfElement{Name: c.rankToName[elem.Val], CumProb: elem.CumProb})
+	}
+	return elems
+}
+
+func (c *NamedCdf) load(elems []namedCdfElement) error {
+	rankToName := map[float64]string{}
+	cdfElems := make([]cdfElement, 0, len(elems))
+	seen := map[string]bool{}
+	for i, elem := range elems {
+		if seen[elem.Name] {
+			return fmt.Errorf("invalid named cdf: duplicate name [%s]", elem.Name)
+		}
+		seen[elem.Name] = true
+		rank := float64(i)
+		rankToName[rank] = elem.Name
+		cdfElems = append(cdfElems, cdfElement{Val: rank, CumProb: elem.CumProb})
+	}
+
+	cdf := &Cdf{}
+	if err := cdf.load(cdfElems); err != nil {
+		return fmt.Errorf("invalid named cdf: %v", err)
+	}
+	c.cdf = cdf
+	c.rankToName = rankToName
+	return nil
+}
+
+func validateProb(prob float64) error {
+	if math.IsNaN(prob) || math.IsInf(prob, 0) {
+		return fmt.Errorf("probability [%v] is not finite", prob)
+	}
+	if prob < 0 {
+		return fmt.Errorf("probability [%v] is negative", prob)
+	}
+	return nil
+}
+
+func validateCumProbs(cumProbs []float64) error {
+	if len(cumProbs) == 0 {
+		return fmt.Errorf("no elements")
+	}
+	prev := 0.0
+	for _, cumProb := range cumProbs {
+		if err := validateProb(cumProb); err != nil {
+			return err
+		}
+		if cumProb < prev {
+			return fmt.Errorf("cumulative probabilities are decreasing at [%v]", cumProb)
+		}
+		prev = cumProb
+	}
+	if math.Abs(prev-1) > cdfTotalTol {
+		return fmt.Errorf("cumulative probabilities end at [%v] rather than 1", prev)
+	}
+	return nil
+}
+
+func gobEncode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobDecode(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func readCSV(r io.Reader, header []string) ([][]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(header)
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header")
+	}
+	for i, field := range records[0] {
+		if field != header[i] {
+			return nil, fmt.Errorf("unexpected header %v, expected %v", records[0], header)
+		}
+	}
+	return records[1:], nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func parseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse [%s] as a number", s)
+	}
+	return f, nil
+}
+
+func parseFloatPair(row []string) (float64, float64, error) {
+	first, err := parseFloat(row[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	second, err := parseFloat(row[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return first, second, nil
+}
@@ -0,0 +1,393 @@
+package prob
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var encodingPmfElements = []*PmfElement{
+	NewPmfElement(3, 0.2),
+	NewPmfElement(1, 0.5),
+	NewPmfElement(2.5, 0.3),
+}
+
+var encodingNamedPmfElements = []*NamedPmfElement{
+	NewNamedPmfElement("c", 0.2),
+	NewNamedPmfElement("a", 0.5),
+	NewNamedPmfElement("b, with comma", 0.3),
+}
+
+func TestPmfRoundTrip(t *testing.T) {
+	p := setupPmf(encodingPmfElements)
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(p)
+		require.Nil(t, err)
+		assert.JSONEq(t, `[{"value":1,"prob":0.5},{"value":2.5,"prob":0.3},{"value":3,"prob":0.2}]`, string(data))
+
+		loaded := NewPmf()
+		require.Nil(t, json.Unmarshal(data, loaded))
+		assert.Equal(t, probMap(p), probMap(loaded))
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data, err := p.MarshalBinary()
+		require.Nil(t, err)
+
+		loaded := NewPmf()
+		require.Nil(t, loaded.UnmarshalBinary(data))
+		assert.Equal(t, probMap(p), probMap(loaded))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Nil(t, p.WriteCSV(&buf))
+		assert.Equal(t, "value,prob\n1,0.5\n2.5,0.3\n3,0.2\n", buf.String())
+
+		loaded, err := ReadPmfCSV(&buf)
+		require.Nil(t, err)
+		assert.Equal(t, probMap(p), probMap(loaded))
+	})
+}
+
+func TestPmfUnmarshalJSONValidation(t *testing.T) {
+	tests := map[string]struct {
+		data      string
+		shouldErr bool
+	}{
+		"malformed": {
+			data:      `{"value":1}`,
+			shouldErr: true,
+		},
+		"null element": {
+			data:      `[null]`,
+			shouldErr: true,
+		},
+		"negative probability": {
+			data:      `[{"value":1,"prob":-0.5}]`,
+			shouldErr: true,
+		},
+		"duplicate value": {
+			data:      `[{"value":1,"prob":0.5},{"value":1,"prob":0.5}]`,
+			shouldErr: true,
+		},
+		"empty": {
+			data:      `[]`,
+			shouldErr: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := NewPmf()
+			err := json.Unmarshal([]byte(test.data), p)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestReadPmfCSVValidation(t *testing.T) {
+	tests := map[string]struct {
+		data string
+	}{
+		"empty input": {
+			data: "",
+		},
+		"wrong header": {
+			data: "val,p\n1,0.5\n",
+		},
+		"wrong number of fields": {
+			data: "value,prob\n1,0.5,2\n",
+		},
+		"unparseable value": {
+			data: "value,prob\none,0.5\n",
+		},
+		"infinite value": {
+			data: "value,prob\n+Inf,0.5\n",
+		},
+		"NaN probability": {
+			data: "value,prob\n1,NaN\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadPmfCSV(strings.NewReader(test.data))
+
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestNamedPmfRoundTrip(t *testing.T) {
+	p := setupNamedPmf(encodingNamedPmfElements)
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(p)
+		require.Nil(t, err)
+
+		loaded := NewNamedPmf()
+		require.Nil(t, json.Unmarshal(data, loaded))
+		assert.Equal(t, p.Items(), loaded.Items())
+		assert.Nil(t, loaded.order)
+	})
+
+	t.Run("json with order", func(t *testing.T) {
+		ordered := setupNamedPmf(encodingNamedPmfElements)
+		ordered.SetOrder("a", "b, with comma", "c")
+
+		data, err := json.Marshal(ordered)
+		require.Nil(t, err)
+
+		loaded := NewNamedPmf()
+		require.Nil(t, json.Unmarshal(data, loaded))
+		assert.Equal(t, ordered.Items(), loaded.Items())
+		assert.Equal(t, ordered.order, loaded.order)
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data, err := p.MarshalBinary()
+		require.Nil(t, err)
+
+		loaded := NewNamedPmf()
+		require.Nil(t, loaded.UnmarshalBinary(data))
+		assert.Equal(t, p.Items(), loaded.Items())
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Nil(t, p.WriteCSV(&buf))
+		assert.Equal(t, "name,prob\nc,0.2\na,0.5\n\"b, with comma\",0.3\n", buf.String())
+
+		loaded, err := ReadNamedPmfCSV(&buf)
+		require.Nil(t, err)
+		assert.Equal(t, p.Items(), loaded.Items())
+	})
+}
+
+func TestNamedPmfUnmarshalJSONValidation(t *testing.T) {
+	tests := map[string]struct {
+		data      string
+		shouldErr bool
+	}{
+		"malformed": {
+			data:      `[]`,
+			shouldErr: true,
+		},
+		"negative probability": {
+			data:      `{"elements":[{"name":"a","prob":-1}]}`,
+			shouldErr: true,
+		},
+		"duplicate name": {
+			data:      `{"elements":[{"name":"a","prob":1},{"name":"a","prob":1}]}`,
+			shouldErr: true,
+		},
+		"ordering missing a name": {
+			data:      `{"elements":[{"name":"a","prob":1},{"name":"b","prob":1}],"order":["a"]}`,
+			shouldErr: true,
+		},
+		"valid": {
+			data:      `{"elements":[{"name":"a","prob":1},{"name":"b","prob":1}],"order":["b","a"]}`,
+			shouldErr: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := NewNamedPmf()
+			err := json.Unmarshal([]byte(test.data), p)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestSuiteRoundTrip(t *testing.T) {
+	s := NewSuite(encodingPmfElements...)
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(s)
+		require.Nil(t, err)
+
+		loaded := &Suite{}
+		require.Nil(t, json.Unmarshal(data, loaded))
+		assert.Equal(t, probMap(s), probMap(loaded))
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data, err := s.MarshalBinary()
+		require.Nil(t, err)
+
+		loaded := &Suite{}
+		require.Nil(t, loaded.UnmarshalBinary(data))
+		assert.Equal(t, probMap(s), probMap(loaded))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Nil(t, s.WriteCSV(&buf))
+
+		loaded, err := ReadSuiteCSV(&buf)
+		require.Nil(t, err)
+		assert.Equal(t, probMap(s), probMap(loaded))
+	})
+
+	t.Run("loaded suite is normalized", func(t *testing.T) {
+		loaded := &Suite{}
+		require.Nil(t, json.Unmarshal([]byte(`[{"value":1,"prob":1},{"value":2,"prob":3}]`), loaded))
+		assert.Equal(t, 0.25, loaded.Prob(1))
+		assert.Equal(t, 0.75, loaded.Prob(2))
+	})
+}
+
+func TestNamedSuiteRoundTrip(t *testing.T) {
+	s := NewNamedSuite(encodingNamedPmfElements...)
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(s)
+		require.Nil(t, err)
+
+		loaded := &NamedSuite{}
+		require.Nil(t, json.Unmarshal(data, loaded))
+		assert.Equal(t, s.Items(), loaded.Items())
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data, err := s.MarshalBinary()
+		require.Nil(t, err)
+
+		loaded := &NamedSuite{}
+		require.Nil(t, loaded.UnmarshalBinary(data))
+		assert.Equal(t, s.Items(), loaded.Items())
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Nil(t, s.WriteCSV(&buf))
+
+		loaded, err := ReadNamedSuiteCSV(&buf)
+		require.Nil(t, err)
+		assert.Equal(t, s.Items(), loaded.Items())
+	})
+}
+
+func TestCdfRoundTrip(t *testing.T) {
+	c, err := NewCdf(map[float64]float64{1: 0.25, 2: 0.25, 4: 0.5})
+	require.Nil(t, err)
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(c)
+		require.Nil(t, err)
+		assert.JSONEq(t, `[{"value":1,"cumProb":0.25},{"value":2,"cumProb":0.5},{"value":4,"cumProb":1}]`, string(data))
+
+		loaded := &Cdf{}
+		require.Nil(t, json.Unmarshal(data, loaded))
+		assert.Equal(t, c, loaded)
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data, err := c.MarshalBinary()
+		require.Nil(t, err)
+
+		loaded := &Cdf{}
+		require.Nil(t, loaded.UnmarshalBinary(data))
+		assert.Equal(t, c, loaded)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Nil(t, c.WriteCSV(&buf))
+		assert.Equal(t, "value,cumprob\n1,0.25\n2,0.5\n4,1\n", buf.String())
+
+		loaded, err := ReadCdfCSV(&buf)
+		require.Nil(t, err)
+		assert.Equal(t, c, loaded)
+	})
+}
+
+func TestCdfUnmarshalJSONValidation(t *testing.T) {
+	tests := map[string]struct {
+		data string
+	}{
+		"empty": {
+			data: `[]`,
+		},
+		"values not increasing": {
+			data: `[{"value":2,"cumProb":0.5},{"value":1,"cumProb":1}]`,
+		},
+		"repeated value": {
+			data: `[{"value":1,"cumProb":0.5},{"value":1,"cumProb":1}]`,
+		},
+		"cumulative probabilities decreasing": {
+			data: `[{"value":1,"cumProb":0.5},{"value":2,"cumProb":0.25},{"value":3,"cumProb":1}]`,
+		},
+		"cumulative probabilities do not end at 1": {
+			data: `[{"value":1,"cumProb":0.5},{"value":2,"cumProb":0.75}]`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Cdf{}
+			err := json.Unmarshal([]byte(test.data), c)
+
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestNamedCdfRoundTrip(t *testing.T) {
+	c, err := NewNamedCdf(
+		map[string]float64{"low": 0.25, "medium": 0.25, "high": 0.5},
+		[]string{"low", "medium", "high"},
+	)
+	require.Nil(t, err)
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(c)
+		require.Nil(t, err)
+		assert.JSONEq(t, `[{"name":"low","cumProb":0.25},{"name":"medium","cumProb":0.5},{"name":"high","cumProb":1}]`, string(data))
+
+		loaded := &NamedCdf{}
+		require.Nil(t, json.Unmarshal(data, loaded))
+		assert.Equal(t, c, loaded)
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data, err := c.MarshalBinary()
+		require.Nil(t, err)
+
+		loaded := &NamedCdf{}
+		require.Nil(t, loaded.UnmarshalBinary(data))
+		assert.Equal(t, c, loaded)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Nil(t, c.WriteCSV(&buf))
+
+		loaded, err := ReadNamedCdfCSV(&buf)
+		require.Nil(t, err)
+		assert.Equal(t, c, loaded)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		loaded := &NamedCdf{}
+		err := json.Unmarshal([]byte(`[{"name":"a","cumProb":0.5},{"name":"a","cumProb":1}]`), loaded)
+		require.NotNil(t, err)
+	})
+}
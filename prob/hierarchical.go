@@ -0,0 +1,151 @@
+package prob
+
+import (
+	"fmt"
+)
+
+// ChildSuiteFactory returns the prior Suite over a lower-level parameter conditioned on a
+// hypothesis about the population parameter
+type ChildSuiteFactory func(hypo float64) *Suite
+
+// HierarchicalSuite is a two-level suite: each hypothesis about a population parameter owns one
+// child Suite per group over that group's own parameter, and the hypothesis is weighted by the
+// evidence its children assign to the observations
+type HierarchicalSuite struct {
+	parent *Suite
+	// children[i][g] is the child Suite of group g for the hypothesis parent.vals[i]
+	children [][]*Suite
+}
+
+// NewHierarchicalSuite creates a new HierarchicalSuite with nGroups groups, each of which is
+// given a child Suite per hypothesis of the parent created by the factory
+func NewHierarchicalSuite(parent *Suite, nGroups int, child ChildSuiteFactory) (h *HierarchicalSuite, err error) {
+	if parent == nil || len(parent.vals) == 0 {
+		return h, fmt.Errorf("unable to create hierarchical suite: parent suite must contain hypotheses")
+	}
+	if nGroups < 1 {
+		return h, fmt.Errorf("unable to create hierarchical suite: number of groups [%d] must be positive", nGroups)
+	}
+	if child == nil {
+		return h, fmt.Errorf("unable to create hierarchical suite: child suite factory is required")
+	}
+
+	h = &HierarchicalSuite{
+		parent:   &Suite{Pmf: parent.Copy()},
+		children: make([][]*Suite, len(parent.vals)),
+	}
+	h.parent.Normalize()
+
+	for i, hypo := range h.parent.vals {
+		h.children[i] = make([]*Suite, nGroups)
+		for g := range h.children[i] {
+			c := child(hypo)
+			if c == nil || len(c.vals) == 0 {
+				return nil, fmt.Errorf("unable to create hierarchical suite: empty child suite for hypothesis [%v]", hypo)
+			}
+			// copy so that groups never share storage even if the factory reuses a Suite
+			cs := &Suite{Pmf: c.Copy()}
+			cs.Normalize()
+			h.children[i][g] = cs
+		}
+	}
+	return h, nil
+}
+
+// NumGroups returns the number of groups
+func (h *HierarchicalSuite) NumGroups() int {
+	return len(h.children[0])
+}
+
+// Update updates the child suites of a group based on an observation and reweights each
+// hypothesis of the parent by the evidence of its child suite; the suite is left unchanged
+// if the observation has zero probability under every hypothesis
+func (h *HierarchicalSuite) Update(group int, ob SuiteObservation) error {
+	if err := h.validateGroup(group); err != nil {
+		return err
+	}
+
+	likes := make([][]float64, len(h.children))
+	evidence := make([]float64, len(h.children))
+	total := 0.0
+	for i, groups := range h.children {
+		c := groups[group]
+		likes[i] = make([]float64, len(c.vals))
+		for j, val := range c.vals {
+			likes[i][j] = ob.GetLikelihood(val)
+			evidence[i] += c.probs[j] * likes[i][j]
+		}
+		total += h.parent.probs[i] * evidence[i]
+	}
+	if total == 0 {
+		return fmt.Errorf("unable to update hierarchical suite: observation has zero probability")
+	}
+
+	for i, groups := range h.children {
+		h.parent.probs[i] *= evidence[i]
+		if evidence[i] == 0 {
+			// the hypothesis is ruled out so its child is left as it was
+			continue
+		}
+		c := groups[group]
+		for j := range c.probs {
+			c.probs[j] *= likes[i][j]
+		}
+		c.invalidate()
+		c.Normalize()
+	}
+	h.parent.invalidate()
+	h.parent.Normalize()
+	return nil
+}
+
+// UpdateSet updates the child suites of a group based on multiple observations
+func (h *HierarchicalSuite) UpdateSet(group int, obs []SuiteObservation) error {
+	for i, ob := range obs {
+		if err := h.Update(group, ob); err != nil {
+			return fmt.Errorf("observation %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Parent returns the marginal distribution of the population parameter
+func (h *HierarchicalSuite) Parent() *Pmf {
+	return h.parent.Copy()
+}
+
+// Child returns the distribution of the parameter of a group conditioned on a hypothesis
+// about the population parameter
+func (h *HierarchicalSuite) Child(hypo float64, group int) (*Pmf, error) {
+	if err := h.validateGroup(group); err != nil {
+		return NewPmf(), err
+	}
+	i, ok := h.parent.index(hypo)
+	if !ok {
+		return NewPmf(), fmt.Errorf("hypothesis [%v] does not exist in hierarchical suite", hypo)
+	}
+	return h.children[i][group].Copy(), nil
+}
+
+// Marginal returns the marginal distribution of the parameter of a group, summing over the
+// hypotheses about the population parameter
+func (h *HierarchicalSuite) Marginal(group int) (*Pmf, error) {
+	if err := h.validateGroup(group); err != nil {
+		return NewPmf(), err
+	}
+	m := map[float64]float64{}
+	for i, groups := range h.children {
+		weight := h.parent.probs[i]
+		for val, prob := range groups[group].All() {
+			m[val] += weight * prob
+		}
+	}
+	return newPmfFromMap(m), nil
+}
+
+func (h *HierarchicalSuite) validateGroup(group int) error {
+	if group < 0 || group >= h.NumGroups() {
+		return fmt.Errorf("group [%d] is outside of range [0, %d)", group, h.NumGroups())
+	}
+	return nil
+}
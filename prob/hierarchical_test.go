@@ -0,0 +1,161 @@
+package prob
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// centeredChild is a child prior over 1, ..., 6 concentrated around twice the population hypothesis
+func centeredChild(hypo float64) *Suite {
+	elems := []*PmfElement{}
+	for x := 1.0; x <= 6; x++ {
+		elems = append(elems, NewPmfElement(x, math.Exp(-math.Abs(x-2*hypo))))
+	}
+	return NewSuite(elems...)
+}
+
+func setupHierarchicalSuite(t *testing.T, nGroups int) *HierarchicalSuite {
+	parent := NewSuite(NewPmfElement(1, 1), NewPmfElement(2, 1), NewPmfElement(3, 2))
+	h, err := NewHierarchicalSuite(parent, nGroups, centeredChild)
+	require.Nil(t, err)
+	return h
+}
+
+func TestNewHierarchicalSuite(t *testing.T) {
+	tests := map[string]struct {
+		parent    *Suite
+		nGroups   int
+		child     ChildSuiteFactory
+		shouldErr bool
+	}{
+		"nil parent": {
+			parent:    nil,
+			nGroups:   1,
+			child:     centeredChild,
+			shouldErr: true,
+		},
+		"empty parent": {
+			parent:    NewSuite(),
+			nGroups:   1,
+			child:     centeredChild,
+			shouldErr: true,
+		},
+		"zero groups": {
+			parent:    NewSuite(NewPmfElement(1, 1)),
+			nGroups:   0,
+			child:     centeredChild,
+			shouldErr: true,
+		},
+		"nil factory": {
+			parent:    NewSuite(NewPmfElement(1, 1)),
+			nGroups:   1,
+			child:     nil,
+			shouldErr: true,
+		},
+		"empty child": {
+			parent:  NewSuite(NewPmfElement(1, 1)),
+			nGroups: 1,
+			child: func(float64) *Suite {
+				return NewSuite()
+			},
+			shouldErr: true,
+		},
+		"valid": {
+			parent:  NewSuite(NewPmfElement(1, 1), NewPmfElement(2, 3)),
+			nGroups: 2,
+			child:   centeredChild,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h, err := NewHierarchicalSuite(test.parent, test.nGroups, test.child)
+			if test.shouldErr {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, test.nGroups, h.NumGroups())
+			assert.InDelta(t, 0.25, h.Parent().Prob(1), float64EqualTol)
+			assert.InDelta(t, 0.75, h.Parent().Prob(2), float64EqualTol)
+		})
+	}
+}
+
+func TestHierarchicalSuiteMatchesJoint(t *testing.T) {
+	h := setupHierarchicalSuite(t, 2)
+	obs := map[int][]SuiteObservation{
+		0: {&suiteTestObservation{2}, &suiteTestObservation{4}},
+		1: {&suiteTestObservation{1}, &suiteTestObservation{1}, &suiteTestObservation{3}},
+	}
+	prior := h.Parent()
+	for group := 0; group < h.NumGroups(); group++ {
+		require.Nil(t, h.UpdateSet(group, obs[group]))
+	}
+
+	// brute force the joint posterior over the population parameter and both group parameters
+	parent := map[float64]float64{}
+	marginals := []map[float64]float64{{}, {}}
+	total := 0.0
+	for hypo, pr := range prior.All() {
+		c := centeredChild(hypo)
+		for x0, p0 := range c.All() {
+			for x1, p1 := range c.All() {
+				joint := pr * p0 * p1
+				for _, ob := range obs[0] {
+					joint *= ob.GetLikelihood(x0)
+				}
+				for _, ob := range obs[1] {
+					joint *= ob.GetLikelihood(x1)
+				}
+				parent[hypo] += joint
+				marginals[0][x0] += joint
+				marginals[1][x1] += joint
+				total += joint
+			}
+		}
+	}
+
+	for hypo, pr := range h.Parent().All() {
+		assert.InDelta(t, parent[hypo]/total, pr, float64EqualTol)
+	}
+	for group := 0; group < h.NumGroups(); group++ {
+		m, err := h.Marginal(group)
+		require.Nil(t, err)
+		for val, pr := range m.All() {
+			assert.InDelta(t, marginals[group][val]/total, pr, float64EqualTol)
+		}
+		assert.InDelta(t, 1, getSum(probMap(m)), float64EqualTol)
+	}
+}
+
+func TestHierarchicalSuiteChild(t *testing.T) {
+	h := setupHierarchicalSuite(t, 1)
+	require.Nil(t, h.Update(0, &suiteTestObservation{5}))
+
+	c, err := h.Child(1, 0)
+	require.Nil(t, err)
+	expected := centeredChild(1)
+	expected.Update(&suiteTestObservation{5})
+	assert.Equal(t, probMap(expected), probMap(c))
+
+	_, err = h.Child(4, 0)
+	assert.NotNil(t, err)
+	_, err = h.Child(1, 1)
+	assert.NotNil(t, err)
+	_, err = h.Marginal(-1)
+	assert.NotNil(t, err)
+	assert.NotNil(t, h.Update(1, &suiteTestObservation{5}))
+}
+
+func TestHierarchicalSuiteZeroProbabilityObservation(t *testing.T) {
+	h := setupHierarchicalSuite(t, 1)
+	before := probMap(h.Parent())
+
+	err := h.Update(0, &suiteTestObservation{7})
+	assert.NotNil(t, err)
+	assert.Equal(t, before, probMap(h.Parent()))
+}
@@ -0,0 +1,105 @@
+package prob
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Recorder is the interface that must be satisfied to record the state of a Suite as it is updated
+type Recorder interface {
+	Record(step int, s *Suite) error
+}
+
+// PosteriorSummary contains summary statistics of a Suite after an update step
+type PosteriorSummary struct {
+	Step              int     `json:"step"`
+	Mean              float64 `json:"mean"`
+	MaximumLikelihood float64 `json:"maximumLikelihood"`
+	CILower           float64 `json:"ciLower"`
+	CIUpper           float64 `json:"ciUpper"`
+}
+
+// SummaryHistory records summary statistics of a Suite after each update step
+type SummaryHistory struct {
+	ciLength  float64
+	mu        sync.Mutex
+	summaries []*PosteriorSummary
+}
+
+// NewSummaryHistory creates a new SummaryHistory recording credible intervals of specified length
+func NewSummaryHistory(ciLength float64) *SummaryHistory {
+	return &SummaryHistory{
+		ciLength: ciLength,
+	}
+}
+
+// Record records summary statistics of a Suite
+func (h *SummaryHistory) Record(step int, s *Suite) error {
+	mean, err := s.Mean()
+	if err != nil {
+		return fmt.Errorf("unable to record step %d: %v", step, err)
+	}
+	mle, err := s.MaximumLikelihood()
+	if err != nil {
+		return fmt.Errorf("unable to record step %d: %v", step, err)
+	}
+	lower, upper, err := s.CredibleInterval(h.ciLength)
+	if err != nil {
+		return fmt.Errorf("unable to record step %d: %v", step, err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.summaries = append(h.summaries, &PosteriorSummary{
+		Step:              step,
+		Mean:              mean,
+		MaximumLikelihood: mle,
+		CILower:           lower,
+		CIUpper:           upper,
+	})
+	return nil
+}
+
+// Summaries returns the recorded summaries in order of recording
+func (h *SummaryHistory) Summaries() []*PosteriorSummary {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*PosteriorSummary{}, h.summaries...)
+}
+
+// SnapshotHistory records a copy of the Pmf of a Suite after each update step
+type SnapshotHistory struct {
+	mu        sync.Mutex
+	steps     []int
+	snapshots []*Pmf
+}
+
+// NewSnapshotHistory creates a new SnapshotHistory
+func NewSnapshotHistory() *SnapshotHistory {
+	return &SnapshotHistory{}
+}
+
+// Record records a copy of the Pmf of a Suite
+func (h *SnapshotHistory) Record(step int, s *Suite) error {
+	snapshot := s.Copy()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.steps = append(h.steps, step)
+	h.snapshots = append(h.snapshots, snapshot)
+	return nil
+}
+
+// Steps returns the recorded steps in order of recording
+func (h *SnapshotHistory) Steps() []int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]int{}, h.steps...)
+}
+
+// Snapshots returns the recorded Pmfs in order of recording
+func (h *SnapshotHistory) Snapshots() []*Pmf {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*Pmf{}, h.snapshots...)
+}
@@ -0,0 +1,55 @@
+package prob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryHistoryRecord(t *testing.T) {
+	t.Run("records summary statistics", func(t *testing.T) {
+		s := NewSuite(
+			NewPmfElement(1, 0.25),
+			NewPmfElement(2, 0.5),
+			NewPmfElement(3, 0.25),
+		)
+		h := NewSummaryHistory(50)
+
+		require.Nil(t, h.Record(0, s))
+		s.Update(&suiteTestObservation{3})
+		require.Nil(t, h.Record(1, s))
+
+		expected := []*PosteriorSummary{
+			{Step: 0, Mean: 2, MaximumLikelihood: 2, CILower: 1, CIUpper: 2},
+			{Step: 1, Mean: 3, MaximumLikelihood: 3, CILower: 3, CIUpper: 3},
+		}
+		assert.Equal(t, expected, h.Summaries())
+	})
+
+	t.Run("empty suite", func(t *testing.T) {
+		h := NewSummaryHistory(90)
+
+		err := h.Record(0, NewSuite())
+
+		require.NotNil(t, err)
+		assert.Empty(t, h.Summaries())
+	})
+}
+
+func TestSnapshotHistoryRecord(t *testing.T) {
+	t.Run("snapshots are independent of suite", func(t *testing.T) {
+		s := NewSuite(suiteUpdateHypos...)
+		h := NewSnapshotHistory()
+
+		require.Nil(t, h.Record(0, s))
+		s.Update(&suiteTestObservation{4})
+		require.Nil(t, h.Record(1, s))
+
+		snapshots := h.Snapshots()
+		require.Len(t, snapshots, 2)
+		assert.Equal(t, 0.25, snapshots[0].Prob(2))
+		assert.Equal(t, 0.0, snapshots[1].Prob(2))
+		assert.Equal(t, []int{0, 1}, h.Steps())
+	})
+}
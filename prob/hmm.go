@@ -0,0 +1,250 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+)
+
+// TransitionProb returns the (unnormalized) probability of moving from one hypothesis to another
+// between consecutive observations
+type TransitionProb func(from float64, to float64) float64
+
+// HMM is a hidden Markov model whose hidden states are the hypotheses of a Suite
+type HMM struct {
+	states     []float64
+	initial    []float64
+	transition [][]float64
+}
+
+// NewHMM creates a new HMM with the hypotheses of the suite as states and the probabilities of the
+// suite as the initial distribution; transition probabilities out of each state are normalized
+func NewHMM(initial *Suite, transition TransitionProb) (h *HMM, err error) {
+	if initial == nil || len(initial.vals) == 0 {
+		return h, fmt.Errorf("unable to create HMM: initial suite must contain hypotheses")
+	}
+	if transition == nil {
+		return h, fmt.Errorf("unable to create HMM: transition probability is required")
+	}
+
+	states := initial.Values()
+	n := len(states)
+	h = &HMM{
+		states:     states,
+		initial:    make([]float64, n),
+		transition: make([][]float64, n),
+	}
+
+	total := 0.0
+	for i, state := range states {
+		h.initial[i] = initial.Prob(state)
+		total += h.initial[i]
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("unable to create HMM: all initial probabilities are 0")
+	}
+	for i := range h.initial {
+		h.initial[i] /= total
+	}
+
+	for i, from := range states {
+		row := make([]float64, n)
+		sum := 0.0
+		for j, to := range states {
+			pr := transition(from, to)
+			if pr < 0 || math.IsNaN(pr) || math.IsInf(pr, 0) {
+				return nil, fmt.Errorf("unable to create HMM: invalid transition probability [%v] from [%v] to [%v]", pr, from, to)
+			}
+			row[j] = pr
+			sum += pr
+		}
+		if sum == 0 {
+			return nil, fmt.Errorf("unable to create HMM: no transitions out of state [%v]", from)
+		}
+		for j := range row {
+			row[j] /= sum
+		}
+		h.transition[i] = row
+	}
+	return h, nil
+}
+
+// Filter returns the distribution of the hidden state at each step given the observations up to
+// and including that step (forward filtering)
+func (h *HMM) Filter(obs []SuiteObservation) ([]*Pmf, error) {
+	alpha, _, err := h.forward(obs)
+	if err != nil {
+		return nil, err
+	}
+	return h.pmfs(alpha), nil
+}
+
+// Smooth returns the distribution of the hidden state at each step given all of the observations
+// (forward-backward smoothing)
+func (h *HMM) Smooth(obs []SuiteObservation) ([]*Pmf, error) {
+	alpha, scale, err := h.forward(obs)
+	if err != nil {
+		return nil, err
+	}
+	n := len(h.states)
+
+	// backward pass using the forward scaling factors for numerical stability
+	beta := make([]float64, n)
+	for i := range beta {
+		beta[i] = 1
+	}
+	posterior := make([][]float64, len(obs))
+	for t := len(obs) - 1; t >= 0; t-- {
+		posterior[t] = make([]float64, n)
+		for i := range beta {
+			posterior[t][i] = alpha[t][i] * beta[i]
+		}
+		if t == 0 {
+			break
+		}
+
+		emission := h.emission(obs[t])
+		prev := make([]float64, n)
+		for i := range prev {
+			for j := range beta {
+				prev[i] += h.transition[i][j] * emission[j] * beta[j]
+			}
+			prev[i] /= scale[t]
+		}
+		beta = prev
+	}
+	return h.pmfs(posterior), nil
+}
+
+// Viterbi returns the most likely sequence of hidden states given the observations
+func (h *HMM) Viterbi(obs []SuiteObservation) ([]float64, error) {
+	if len(obs) == 0 {
+		return []float64{}, nil
+	}
+	n := len(h.states)
+
+	// work in log space to avoid underflow over long sequences
+	logDelta := make([]float64, n)
+	emission := h.emission(obs[0])
+	for i := range logDelta {
+		logDelta[i] = math.Log(h.initial[i]) + math.Log(emission[i])
+	}
+	if isAllNegInf(logDelta) {
+		return nil, fmt.Errorf("unable to decode HMM: observation 0 has zero probability")
+	}
+
+	backPointers := make([][]int, len(obs))
+	for t := 1; t < len(obs); t++ {
+		emission := h.emission(obs[t])
+		next := make([]float64, n)
+		backPointers[t] = make([]int, n)
+		for j := range next {
+			best, bestIdx := math.Inf(-1), 0
+			for i := range logDelta {
+				score := logDelta[i] + math.Log(h.transition[i][j])
+				if score > best {
+					best, bestIdx = score, i
+				}
+			}
+			next[j] = best + math.Log(emission[j])
+			backPointers[t][j] = bestIdx
+		}
+		if isAllNegInf(next) {
+			return nil, fmt.Errorf("unable to decode HMM: observation %d has zero probability", t)
+		}
+		logDelta = next
+	}
+
+	last := 0
+	for i := range logDelta {
+		if logDelta[i] > logDelta[last] {
+			last = i
+		}
+	}
+	path := make([]float64, len(obs))
+	for t := len(obs) - 1; t > 0; t-- {
+		path[t] = h.states[last]
+		last = backPointers[t][last]
+	}
+	path[0] = h.states[last]
+	return path, nil
+}
+
+// LogLikelihood returns the log probability of the observations under the model
+func (h *HMM) LogLikelihood(obs []SuiteObservation) (float64, error) {
+	_, scale, err := h.forward(obs)
+	if err != nil {
+		return 0, err
+	}
+	ll := 0.0
+	for _, c := range scale {
+		ll += math.Log(c)
+	}
+	return ll, nil
+}
+
+// forward returns the normalized forward probabilities at each step along with the normalizing
+// factor at each step, which is the probability of the observation given the previous observations
+func (h *HMM) forward(obs []SuiteObservation) (alpha [][]float64, scale []float64, err error) {
+	n := len(h.states)
+	alpha = make([][]float64, len(obs))
+	scale = make([]float64, len(obs))
+
+	prev := h.initial
+	for t, ob := range obs {
+		emission := h.emission(ob)
+		cur := make([]float64, n)
+		sum := 0.0
+		for j := range cur {
+			pred := 0.0
+			if t == 0 {
+				pred = prev[j]
+			} else {
+				for i := range prev {
+					pred += prev[i] * h.transition[i][j]
+				}
+			}
+			cur[j] = pred * emission[j]
+			sum += cur[j]
+		}
+		if sum == 0 {
+			return nil, nil, fmt.Errorf("unable to update HMM: observation %d has zero probability", t)
+		}
+		for j := range cur {
+			cur[j] /= sum
+		}
+		alpha[t] = cur
+		scale[t] = sum
+		prev = cur
+	}
+	return alpha, scale, nil
+}
+
+func (h *HMM) emission(ob SuiteObservation) []float64 {
+	e := make([]float64, len(h.states))
+	for i, state := range h.states {
+		e[i] = ob.GetLikelihood(state)
+	}
+	return e
+}
+
+func (h *HMM) pmfs(probs [][]float64) []*Pmf {
+	pmfs := make([]*Pmf, 0, len(probs))
+	for _, step := range probs {
+		p := &Pmf{
+			vals:  append([]float64{}, h.states...),
+			probs: append([]float64{}, step...),
+		}
+		p.Normalize()
+		pmfs = append(pmfs, p)
+	}
+	return pmfs
+}
+
+func isAllNegInf(x []float64) bool {
+	for _, val := range x {
+		if !math.IsInf(val, -1) {
+			return false
+		}
+	}
+	return true
+}
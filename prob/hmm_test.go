@@ -0,0 +1,232 @@
+package prob
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coinFlipObservation is a flip of either a fair coin (hypothesis 0) or a biased coin (hypothesis 1)
+type coinFlipObservation struct {
+	heads bool
+}
+
+func (o *coinFlipObservation) GetLikelihood(hypo float64) float64 {
+	pHeads := 0.5
+	if hypo == 1 {
+		pHeads = 0.9
+	}
+	if o.heads {
+		return pHeads
+	}
+	return 1 - pHeads
+}
+
+func stickyTransition(from float64, to float64) float64 {
+	if from == to {
+		return 0.8
+	}
+	return 0.2
+}
+
+func setupCoinHMM(t *testing.T) *HMM {
+	h, err := NewHMM(
+		NewSuite(NewPmfElement(0, 2), NewPmfElement(1, 1)),
+		stickyTransition,
+	)
+	require.Nil(t, err)
+	return h
+}
+
+func coinFlips(flips ...bool) []SuiteObservation {
+	obs := []SuiteObservation{}
+	for _, heads := range flips {
+		obs = append(obs, &coinFlipObservation{heads})
+	}
+	return obs
+}
+
+// enumeratePaths computes the joint probability of every sequence of hidden states and the observations
+func enumeratePaths(h *HMM, obs []SuiteObservation) map[[4]int]float64 {
+	n := len(h.states)
+	joint := map[[4]int]float64{}
+	var recurse func(t int, path [4]int, prob float64)
+	recurse = func(t int, path [4]int, prob float64) {
+		if t == len(obs) {
+			joint[path] = prob
+			return
+		}
+		for j := 0; j < n; j++ {
+			p := prob
+			if t == 0 {
+				p *= h.initial[j]
+			} else {
+				p *= h.transition[path[t-1]][j]
+			}
+			p *= obs[t].GetLikelihood(h.states[j])
+			path[t] = j
+			recurse(t+1, path, p)
+		}
+	}
+	recurse(0, [4]int{}, 1)
+	return joint
+}
+
+func TestNewHMM(t *testing.T) {
+	tests := map[string]struct {
+		initial    *Suite
+		transition TransitionProb
+		shouldErr  bool
+	}{
+		"nil suite": {
+			initial:    nil,
+			transition: stickyTransition,
+			shouldErr:  true,
+		},
+		"empty suite": {
+			initial:    NewSuite(),
+			transition: stickyTransition,
+			shouldErr:  true,
+		},
+		"nil transition": {
+			initial:    NewSuite(NewPmfElement(0, 1)),
+			transition: nil,
+			shouldErr:  true,
+		},
+		"negative transition": {
+			initial:    NewSuite(NewPmfElement(0, 1)),
+			transition: func(from, to float64) float64 { return -1 },
+			shouldErr:  true,
+		},
+		"no transitions out of state": {
+			initial:    NewSuite(NewPmfElement(0, 1), NewPmfElement(1, 1)),
+			transition: func(from, to float64) float64 { return from },
+			shouldErr:  true,
+		},
+		"valid": {
+			initial:    NewSuite(NewPmfElement(0, 1), NewPmfElement(1, 1)),
+			transition: func(from, to float64) float64 { return 1 + to },
+			shouldErr:  false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h, err := NewHMM(test.initial, test.transition)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			for _, row := range h.transition {
+				assert.InDelta(t, 1, row[0]+row[1], float64EqualTol)
+			}
+		})
+	}
+}
+
+func TestHMMMatchesEnumeration(t *testing.T) {
+	h := setupCoinHMM(t)
+	obs := coinFlips(true, true, false, true)
+	joint := enumeratePaths(h, obs)
+
+	evidence := 0.0
+	for _, prob := range joint {
+		evidence += prob
+	}
+
+	t.Run("filter", func(t *testing.T) {
+		pmfs, err := h.Filter(obs)
+		require.Nil(t, err)
+		require.Len(t, pmfs, len(obs))
+
+		// the filtered distribution at the last step is the smoothed distribution at the last step
+		expected := [2]float64{}
+		for path, prob := range joint {
+			expected[path[len(obs)-1]] += prob / evidence
+		}
+		assert.InDelta(t, expected[0], pmfs[len(obs)-1].Prob(0), float64EqualTol)
+		assert.InDelta(t, expected[1], pmfs[len(obs)-1].Prob(1), float64EqualTol)
+	})
+
+	t.Run("smooth", func(t *testing.T) {
+		pmfs, err := h.Smooth(obs)
+		require.Nil(t, err)
+		require.Len(t, pmfs, len(obs))
+
+		for step := range obs {
+			expected := [2]float64{}
+			for path, prob := range joint {
+				expected[path[step]] += prob / evidence
+			}
+			assert.InDelta(t, expected[0], pmfs[step].Prob(0), float64EqualTol)
+			assert.InDelta(t, expected[1], pmfs[step].Prob(1), float64EqualTol)
+		}
+	})
+
+	t.Run("viterbi", func(t *testing.T) {
+		path, err := h.Viterbi(obs)
+		require.Nil(t, err)
+
+		var best [4]int
+		bestProb := 0.0
+		for p, prob := range joint {
+			if prob > bestProb {
+				best, bestProb = p, prob
+			}
+		}
+		expected := []float64{}
+		for _, idx := range best {
+			expected = append(expected, h.states[idx])
+		}
+		assert.Equal(t, expected, path)
+	})
+
+	t.Run("log likelihood", func(t *testing.T) {
+		ll, err := h.LogLikelihood(obs)
+		require.Nil(t, err)
+		assert.InDelta(t, math.Log(evidence), ll, float64EqualTol)
+	})
+}
+
+func TestHMMStaticMatchesSuite(t *testing.T) {
+	t.Run("identity transition matches suite update", func(t *testing.T) {
+		h, err := NewHMM(NewSuite(suiteUpdateHypos...), func(from, to float64) float64 {
+			if from == to {
+				return 1
+			}
+			return 0
+		})
+		require.Nil(t, err)
+		obs := []SuiteObservation{&suiteTestObservation{3}, &suiteTestObservation{4}}
+
+		pmfs, err := h.Filter(obs)
+		require.Nil(t, err)
+
+		s := NewSuite(suiteUpdateHypos...)
+		for step, ob := range obs {
+			s.Update(ob)
+			for val, prob := range s.All() {
+				assert.InDelta(t, prob, pmfs[step].Prob(val), float64EqualTol)
+			}
+		}
+	})
+}
+
+func TestHMMZeroProbabilityObservation(t *testing.T) {
+	h, err := NewHMM(NewSuite(suiteUpdateHypos...), func(from, to float64) float64 { return 1 })
+	require.Nil(t, err)
+	obs := []SuiteObservation{&suiteTestObservation{3}, &suiteTestObservation{10}}
+
+	_, err = h.Filter(obs)
+	require.NotNil(t, err)
+	_, err = h.Smooth(obs)
+	require.NotNil(t, err)
+	_, err = h.Viterbi(obs)
+	require.NotNil(t, err)
+	_, err = h.LogLikelihood(obs)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,167 @@
+package prob
+
+import (
+	"fmt"
+)
+
+// LikelihoodFunc is an adapter allowing a function to be used as a SuiteObservation
+type LikelihoodFunc func(hypo float64) float64
+
+// GetLikelihood calls f(hypo)
+func (f LikelihoodFunc) GetLikelihood(hypo float64) float64 {
+	return f(hypo)
+}
+
+// NamedLikelihoodFunc is an adapter allowing a function to be used as a NamedSuiteObservation
+type NamedLikelihoodFunc func(hypo string) float64
+
+// GetLikelihood calls f(hypo)
+func (f NamedLikelihoodFunc) GetLikelihood(hypo string) float64 {
+	return f(hypo)
+}
+
+// ValueLikelihood returns the likelihood of observing a value under a hypothesis
+type ValueLikelihood func(val float64, hypo float64) float64
+
+// Observe returns the observation of a value
+func (f ValueLikelihood) Observe(val float64) SuiteObservation {
+	return LikelihoodFunc(func(hypo float64) float64 {
+		return f(val, hypo)
+	})
+}
+
+// Independent combines independent observations into a single observation whose likelihood is
+// the product of their likelihoods
+func Independent(obs ...SuiteObservation) SuiteObservation {
+	return LikelihoodFunc(func(hypo float64) float64 {
+		like := 1.0
+		for _, ob := range obs {
+			like *= ob.GetLikelihood(hypo)
+			if like == 0 {
+				return 0
+			}
+		}
+		return like
+	})
+}
+
+// NamedIndependent combines independent observations into a single observation whose likelihood is
+// the product of their likelihoods
+func NamedIndependent(obs ...NamedSuiteObservation) NamedSuiteObservation {
+	return NamedLikelihoodFunc(func(hypo string) float64 {
+		like := 1.0
+		for _, ob := range obs {
+			like *= ob.GetLikelihood(hypo)
+			if like == 0 {
+				return 0
+			}
+		}
+		return like
+	})
+}
+
+// Mixture combines observations into a single observation generated by one of them, chosen with
+// probability proportional to its weight
+func Mixture(weights []float64, obs ...SuiteObservation) (SuiteObservation, error) {
+	normalized, err := mixtureWeights(weights, len(obs))
+	if err != nil {
+		return nil, err
+	}
+	return LikelihoodFunc(func(hypo float64) float64 {
+		like := 0.0
+		for i, ob := range obs {
+			like += normalized[i] * ob.GetLikelihood(hypo)
+		}
+		return like
+	}), nil
+}
+
+// NamedMixture combines observations into a single observation generated by one of them, chosen
+// with probability proportional to its weight
+func NamedMixture(weights []float64, obs ...NamedSuiteObservation) (NamedSuiteObservation, error) {
+	normalized, err := mixtureWeights(weights, len(obs))
+	if err != nil {
+		return nil, err
+	}
+	return NamedLikelihoodFunc(func(hypo string) float64 {
+		like := 0.0
+		for i, ob := range obs {
+			like += normalized[i] * ob.GetLikelihood(hypo)
+		}
+		return like
+	}), nil
+}
+
+// WithNoise returns the observation of a value measured with additive error distributed as noise,
+// so that the likelihood of a hypothesis sums the likelihoods of each possible true value
+func WithNoise(measured float64, noise *Pmf, like ValueLikelihood) (SuiteObservation, error) {
+	total := 0.0
+	for _, prob := range noise.probs {
+		total += prob
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("unable to create noisy observation from empty noise pmf or all zero probabilities")
+	}
+
+	errs, probs := noise.Values(), make([]float64, len(noise.probs))
+	for i, prob := range noise.probs {
+		probs[i] = prob / total
+	}
+	return LikelihoodFunc(func(hypo float64) float64 {
+		l := 0.0
+		for i, e := range errs {
+			if probs[i] == 0 {
+				continue
+			}
+			l += probs[i] * like(measured-e, hypo)
+		}
+		return l
+	}), nil
+}
+
+// LikelihoodTable maps each hypothesis to the probabilities of the possible outcomes
+type LikelihoodTable map[float64]map[string]float64
+
+// Observe returns the observation of an outcome; outcomes missing from the table for a
+// hypothesis have likelihood 0
+func (t LikelihoodTable) Observe(outcome string) SuiteObservation {
+	return LikelihoodFunc(func(hypo float64) float64 {
+		return t[hypo][outcome]
+	})
+}
+
+// NamedLikelihoodTable maps each named hypothesis to the probabilities of the possible outcomes
+type NamedLikelihoodTable map[string]map[string]float64
+
+// Observe returns the observation of an outcome; outcomes missing from the table for a
+// hypothesis have likelihood 0
+func (t NamedLikelihoodTable) Observe(outcome string) NamedSuiteObservation {
+	return NamedLikelihoodFunc(func(hypo string) float64 {
+		return t[hypo][outcome]
+	})
+}
+
+func mixtureWeights(weights []float64, n int) ([]float64, error) {
+	if n == 0 {
+		return nil, fmt.Errorf("unable to create mixture: no observations")
+	}
+	if len(weights) != n {
+		return nil, fmt.Errorf("unable to create mixture: %d weights for %d observations", len(weights), n)
+	}
+	total := 0.0
+	for _, w := range weights {
+		if w < 0 {
+			return nil, fmt.Errorf("unable to create mixture: weight [%v] must be non-negative", w)
+		}
+		total += w
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("unable to create mixture: weights must not all be 0")
+	}
+
+	normalized := make([]float64, n)
+	for i, w := range weights {
+		normalized[i] = w / total
+	}
+	return normalized, nil
+}
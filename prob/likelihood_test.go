@@ -0,0 +1,166 @@
+package prob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uniformUpTo is the likelihood of rolling a value on a die with hypo sides
+func uniformUpTo(val float64, hypo float64) float64 {
+	if val < 1 || val > hypo {
+		return 0
+	}
+	return 1 / hypo
+}
+
+func TestLikelihoodFunc(t *testing.T) {
+	ob := LikelihoodFunc(func(hypo float64) float64 {
+		return 2 * hypo
+	})
+	assert.Equal(t, 6.0, ob.GetLikelihood(3))
+
+	named := NamedLikelihoodFunc(func(hypo string) float64 {
+		return float64(len(hypo))
+	})
+	assert.Equal(t, 3.0, named.GetLikelihood("abc"))
+}
+
+func TestValueLikelihoodObserve(t *testing.T) {
+	s := NewSuite(suiteUpdateHypos...)
+	s.Update(ValueLikelihood(uniformUpTo).Observe(3))
+
+	expected := NewSuite(suiteUpdateHypos...)
+	expected.Update(&suiteTestObservation{3})
+	assert.InDeltaMapValues(t, probMap(expected), probMap(s), float64EqualTol)
+}
+
+func TestIndependent(t *testing.T) {
+	obs := []SuiteObservation{&suiteTestObservation{3}, &suiteTestObservation{2}, &suiteTestObservation{4}}
+
+	s := NewSuite(suiteUpdateHypos...)
+	s.Update(Independent(obs...))
+	expected := NewSuite(suiteUpdateHypos...)
+	expected.UpdateSet(obs)
+	assert.InDeltaMapValues(t, probMap(expected), probMap(s), float64EqualTol)
+
+	assert.Equal(t, 1.0, Independent().GetLikelihood(1))
+
+	cookie := NamedLikelihoodTable{
+		"bowl 1": {"vanilla": 0.75, "chocolate": 0.25},
+		"bowl 2": {"vanilla": 0.5, "chocolate": 0.5},
+	}
+	named := NamedIndependent(cookie.Observe("vanilla"), cookie.Observe("chocolate"))
+	assert.Equal(t, 0.1875, named.GetLikelihood("bowl 1"))
+	assert.Equal(t, 0.25, named.GetLikelihood("bowl 2"))
+}
+
+func TestMixture(t *testing.T) {
+	tests := map[string]struct {
+		weights   []float64
+		obs       []SuiteObservation
+		expected  map[float64]float64
+		shouldErr bool
+	}{
+		"no observations": {
+			weights:   []float64{},
+			obs:       []SuiteObservation{},
+			shouldErr: true,
+		},
+		"mismatched weights": {
+			weights:   []float64{1},
+			obs:       []SuiteObservation{&suiteTestObservation{2}, &suiteTestObservation{4}},
+			shouldErr: true,
+		},
+		"negative weight": {
+			weights:   []float64{2, -1},
+			obs:       []SuiteObservation{&suiteTestObservation{2}, &suiteTestObservation{4}},
+			shouldErr: true,
+		},
+		"zero weights": {
+			weights:   []float64{0, 0},
+			obs:       []SuiteObservation{&suiteTestObservation{2}, &suiteTestObservation{4}},
+			shouldErr: true,
+		},
+		"valid": {
+			weights: []float64{3, 1},
+			obs:     []SuiteObservation{&suiteTestObservation{2}, &suiteTestObservation{4}},
+			expected: map[float64]float64{
+				1: 0,
+				2: 0.375,
+				4: 0.25,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ob, err := Mixture(test.weights, test.obs...)
+			if test.shouldErr {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			for hypo, like := range test.expected {
+				assert.InDelta(t, like, ob.GetLikelihood(hypo), float64EqualTol)
+			}
+		})
+	}
+}
+
+func TestNamedMixture(t *testing.T) {
+	cookie := NamedLikelihoodTable{
+		"bowl 1": {"vanilla": 0.75, "chocolate": 0.25},
+	}
+	_, err := NamedMixture([]float64{1}, cookie.Observe("vanilla"), cookie.Observe("chocolate"))
+	assert.NotNil(t, err)
+
+	ob, err := NamedMixture([]float64{1, 1}, cookie.Observe("vanilla"), cookie.Observe("chocolate"))
+	require.Nil(t, err)
+	assert.Equal(t, 0.5, ob.GetLikelihood("bowl 1"))
+	assert.Equal(t, 0.0, ob.GetLikelihood("bowl 2"))
+}
+
+func TestWithNoise(t *testing.T) {
+	_, err := WithNoise(3, NewPmf(), uniformUpTo)
+	assert.NotNil(t, err)
+
+	t.Run("no noise", func(t *testing.T) {
+		ob, err := WithNoise(3, newPmfFromMap(map[float64]float64{0: 1}), uniformUpTo)
+		require.Nil(t, err)
+		for _, hypo := range []float64{2, 3, 4} {
+			assert.Equal(t, uniformUpTo(3, hypo), ob.GetLikelihood(hypo))
+		}
+	})
+
+	t.Run("noise", func(t *testing.T) {
+		// the measured value 5 is the true value plus an error of 0 or +1 with equal probability
+		noise := newPmfFromMap(map[float64]float64{0: 1, 1: 1})
+		ob, err := WithNoise(5, noise, uniformUpTo)
+		require.Nil(t, err)
+
+		assert.Equal(t, 0.0, ob.GetLikelihood(3))
+		assert.InDelta(t, 0.5*0.25, ob.GetLikelihood(4), float64EqualTol)
+		assert.InDelta(t, 0.5*0.2+0.5*0.2, ob.GetLikelihood(5), float64EqualTol)
+	})
+}
+
+func TestLikelihoodTable(t *testing.T) {
+	table := LikelihoodTable{
+		1: {"heads": 0.5, "tails": 0.5},
+		2: {"heads": 0.9, "tails": 0.1},
+	}
+	ob := table.Observe("heads")
+	assert.Equal(t, 0.5, ob.GetLikelihood(1))
+	assert.Equal(t, 0.9, ob.GetLikelihood(2))
+	assert.Equal(t, 0.0, ob.GetLikelihood(3))
+	assert.Equal(t, 0.0, table.Observe("edge").GetLikelihood(1))
+
+	named := NamedLikelihoodTable{
+		"bowl 1": {"vanilla": 0.75, "chocolate": 0.25},
+	}
+	assert.Equal(t, 0.75, named.Observe("vanilla").GetLikelihood("bowl 1"))
+	assert.Equal(t, 0.0, named.Observe("vanilla").GetLikelihood("bowl 2"))
+	assert.Equal(t, 0.0, named.Observe("strawberry").GetLikelihood("bowl 1"))
+}
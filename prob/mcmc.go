@@ -0,0 +1,291 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// maxSliceSteps limits the number of steps taken to expand a slice in either direction
+const maxSliceSteps = 100
+
+// maxSliceShrinks limits the number of times a slice is shrunk before a point on it is found
+const maxSliceShrinks = 1000
+
+// LogPosterior computes the unnormalized log posterior density of a vector of parameters
+type LogPosterior func(params []float64) float64
+
+// SamplerConfig contains the settings of a Markov chain Monte Carlo sampler
+type SamplerConfig struct {
+	NSamples int   // number of samples to return
+	BurnIn   int   // number of initial iterations to discard
+	Thin     int   // keep every Thin-th iteration after burn-in; 0 is equivalent to 1
+	Seed     int64 // seed for random number generation; 0 seeds from the current time
+}
+
+func (c *SamplerConfig) validate() error {
+	if c == nil {
+		return fmt.Errorf("sampler config is required")
+	}
+	if c.NSamples <= 0 {
+		return fmt.Errorf("number of samples [%d] must be positive", c.NSamples)
+	}
+	if c.BurnIn < 0 {
+		return fmt.Errorf("burn-in [%d] must be non-negative", c.BurnIn)
+	}
+	if c.Thin < 0 {
+		return fmt.Errorf("thinning [%d] must be non-negative", c.Thin)
+	}
+	return nil
+}
+
+func (c *SamplerConfig) thin() int {
+	if c.Thin == 0 {
+		return 1
+	}
+	return c.Thin
+}
+
+func (c *SamplerConfig) rng() *rand.Rand {
+	return newRand(c.Seed)
+}
+
+// Samples contains draws of a vector of parameters from a Markov chain Monte Carlo sampler
+type Samples struct {
+	draws    [][]float64
+	accepted int
+	proposed int
+}
+
+// Len returns the number of samples
+func (s *Samples) Len() int {
+	return len(s.draws)
+}
+
+// NParams returns the number of parameters in each sample
+func (s *Samples) NParams() int {
+	if len(s.draws) == 0 {
+		return 0
+	}
+	return len(s.draws[0])
+}
+
+// Draw returns a copy of the i-th sample
+func (s *Samples) Draw(i int) []float64 {
+	return append([]float64{}, s.draws[i]...)
+}
+
+// Param returns the samples of the parameter with the specified index
+func (s *Samples) Param(idx int) ([]float64, error) {
+	if idx < 0 || idx >= s.NParams() {
+		return nil, fmt.Errorf("parameter index [%d] is out of range [0, %d)", idx, s.NParams())
+	}
+	vals := make([]float64, 0, len(s.draws))
+	for _, draw := range s.draws {
+		vals = append(vals, draw[idx])
+	}
+	return vals, nil
+}
+
+// AcceptanceRate returns the fraction of proposals accepted after burn-in
+func (s *Samples) AcceptanceRate() float64 {
+	if s.proposed == 0 {
+		return 0
+	}
+	return float64(s.accepted) / float64(s.proposed)
+}
+
+// MakePmf returns a normalized Pmf of the samples of the parameter with the specified index;
+// if binWidth is positive, samples are rounded to the nearest multiple of binWidth
+func (s *Samples) MakePmf(idx int, binWidth float64) (*Pmf, error) {
+	vals, err := s.Param(idx)
+	if err != nil {
+		return nil, err
+	}
+	return samplesToPmf(vals, binWidth), nil
+}
+
+// MakeCdf returns a Cdf of the samples of the parameter with the specified index;
+// if binWidth is positive, samples are rounded to the nearest multiple of binWidth
+func (s *Samples) MakeCdf(idx int, binWidth float64) (*Cdf, error) {
+	p, err := s.MakePmf(idx, binWidth)
+	if err != nil {
+		return nil, err
+	}
+	return p.MakeCdf()
+}
+
+// samplesToPmf returns a normalized Pmf with each sample contributing equal probability
+func samplesToPmf(vals []float64, binWidth float64) *Pmf {
+	counts := map[float64]float64{}
+	for _, val := range vals {
+		if binWidth > 0 {
+			val = math.Round(val/binWidth) * binWidth
+		}
+		counts[val]++
+	}
+	p := newPmfFromMap(counts)
+	p.Normalize()
+	return p
+}
+
+// MetropolisHastings draws samples from a posterior using a random walk Metropolis-Hastings sampler
+// with independent Gaussian proposals of the specified scale (standard deviation) for each parameter
+func MetropolisHastings(
+	logPost LogPosterior,
+	init []float64,
+	proposalScale []float64,
+	cfg *SamplerConfig,
+) (*Samples, error) {
+	if err := validateSamplerInputs(logPost, init, proposalScale, cfg); err != nil {
+		return nil, fmt.Errorf("unable to run Metropolis-Hastings sampler: %v", err)
+	}
+	rng := cfg.rng()
+	thin := cfg.thin()
+
+	cur := append([]float64{}, init...)
+	curLP := logPost(cur)
+	if !isValidLogDensity(curLP) {
+		return nil, fmt.Errorf("unable to run Metropolis-Hastings sampler: initial parameters have zero posterior density")
+	}
+
+	samples := &Samples{}
+	prop := make([]float64, len(cur))
+	nIter := cfg.BurnIn + cfg.NSamples*thin
+	for iter := 0; iter < nIter; iter++ {
+		for i := range cur {
+			prop[i] = cur[i] + proposalScale[i]*rng.NormFloat64()
+		}
+		propLP := logPost(prop)
+
+		accept := isValidLogDensity(propLP) && math.Log(rng.Float64()) < propLP-curLP
+		if accept {
+			copy(cur, prop)
+			curLP = propLP
+		}
+
+		if iter < cfg.BurnIn {
+			continue
+		}
+		samples.proposed++
+		if accept {
+			samples.accepted++
+		}
+		if (iter-cfg.BurnIn)%thin == thin-1 {
+			samples.draws = append(samples.draws, append([]float64{}, cur...))
+		}
+	}
+	return samples, nil
+}
+
+// SliceSample draws samples from a posterior using a coordinate-wise slice sampler with stepping out,
+// where width is the initial slice width for each parameter; every proposal is accepted
+func SliceSample(
+	logPost LogPosterior,
+	init []float64,
+	width []float64,
+	cfg *SamplerConfig,
+) (*Samples, error) {
+	if err := validateSamplerInputs(logPost, init, width, cfg); err != nil {
+		return nil, fmt.Errorf("unable to run slice sampler: %v", err)
+	}
+	rng := cfg.rng()
+	thin := cfg.thin()
+
+	cur := append([]float64{}, init...)
+	curLP := logPost(cur)
+	if !isValidLogDensity(curLP) {
+		return nil, fmt.Errorf("unable to run slice sampler: initial parameters have zero posterior density")
+	}
+
+	// evaluate the log posterior with a single coordinate replaced
+	x := make([]float64, len(cur))
+	logPostAt := func(i int, val float64) float64 {
+		copy(x, cur)
+		x[i] = val
+		return logPost(x)
+	}
+
+	samples := &Samples{}
+	nIter := cfg.BurnIn + cfg.NSamples*thin
+	for iter := 0; iter < nIter; iter++ {
+		for i := range cur {
+			level := curLP + math.Log(rng.Float64())
+
+			// step out to find an interval containing the slice
+			lower := cur[i] - width[i]*rng.Float64()
+			upper := lower + width[i]
+			for step := 0; step < maxSliceSteps && logPostAt(i, lower) > level; step++ {
+				lower -= width[i]
+			}
+			for step := 0; step < maxSliceSteps && logPostAt(i, upper) > level; step++ {
+				upper += width[i]
+			}
+
+			// sample uniformly from the interval, shrinking it on rejection
+			found := false
+			for shrink := 0; shrink < maxSliceShrinks; shrink++ {
+				val := lower + (upper-lower)*rng.Float64()
+				lp := logPostAt(i, val)
+				if isValidLogDensity(lp) && lp > level {
+					cur[i] = val
+					curLP = lp
+					found = true
+					break
+				}
+				if val < cur[i] {
+					lower = val
+				} else {
+					upper = val
+				}
+			}
+			if !found {
+				return samples, fmt.Errorf(
+					"unable to run slice sampler: no point on the slice found for parameter [%d] after %d shrinks",
+					i, maxSliceShrinks,
+				)
+			}
+		}
+
+		if iter < cfg.BurnIn {
+			continue
+		}
+		samples.proposed++
+		samples.accepted++
+		if (iter-cfg.BurnIn)%thin == thin-1 {
+			samples.draws = append(samples.draws, append([]float64{}, cur...))
+		}
+	}
+	return samples, nil
+}
+
+func validateSamplerInputs(logPost LogPosterior, init []float64, scale []float64, cfg *SamplerConfig) error {
+	if logPost == nil {
+		return fmt.Errorf("log posterior is required")
+	}
+	if len(init) == 0 {
+		return fmt.Errorf("initial parameters are required")
+	}
+	if len(scale) != len(init) {
+		return fmt.Errorf("number of scales [%d] does not match number of parameters [%d]", len(scale), len(init))
+	}
+	for _, sc := range scale {
+		if !(sc > 0) || math.IsInf(sc, 0) {
+			return fmt.Errorf("scale [%v] must be positive and finite", sc)
+		}
+	}
+	return cfg.validate()
+}
+
+// newRand creates a random number generator, seeding from the current time if seed is 0
+func newRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+func isValidLogDensity(lp float64) bool {
+	return !math.IsNaN(lp) && !math.IsInf(lp, 0)
+}
@@ -0,0 +1,272 @@
+package prob
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// normalLogPosterior is the log density (up to a constant) of independent normal distributions
+func normalLogPosterior(means []float64, sds []float64) LogPosterior {
+	return func(params []float64) float64 {
+		lp := 0.0
+		for i, x := range params {
+			z := (x - means[i]) / sds[i]
+			lp -= 0.5 * z * z
+		}
+		return lp
+	}
+}
+
+func sampleMean(vals []float64) float64 {
+	sum := 0.0
+	for _, val := range vals {
+		sum += val
+	}
+	return sum / float64(len(vals))
+}
+
+func TestSamplerValidation(t *testing.T) {
+	logPost := normalLogPosterior([]float64{0}, []float64{1})
+	cfg := &SamplerConfig{NSamples: 10, Seed: 1}
+
+	tests := map[string]struct {
+		logPost LogPosterior
+		init    []float64
+		scale   []float64
+		cfg     *SamplerConfig
+	}{
+		"nil log posterior": {
+			logPost: nil,
+			init:    []float64{0},
+			scale:   []float64{1},
+			cfg:     cfg,
+		},
+		"no parameters": {
+			logPost: logPost,
+			init:    []float64{},
+			scale:   []float64{},
+			cfg:     cfg,
+		},
+		"mismatched scale": {
+			logPost: logPost,
+			init:    []float64{0},
+			scale:   []float64{1, 1},
+			cfg:     cfg,
+		},
+		"non-positive scale": {
+			logPost: logPost,
+			init:    []float64{0},
+			scale:   []float64{0},
+			cfg:     cfg,
+		},
+		"nil config": {
+			logPost: logPost,
+			init:    []float64{0},
+			scale:   []float64{1},
+			cfg:     nil,
+		},
+		"non-positive number of samples": {
+			logPost: logPost,
+			init:    []float64{0},
+			scale:   []float64{1},
+			cfg:     &SamplerConfig{NSamples: 0},
+		},
+		"negative burn-in": {
+			logPost: logPost,
+			init:    []float64{0},
+			scale:   []float64{1},
+			cfg:     &SamplerConfig{NSamples: 10, BurnIn: -1},
+		},
+		"negative thinning": {
+			logPost: logPost,
+			init:    []float64{0},
+			scale:   []float64{1},
+			cfg:     &SamplerConfig{NSamples: 10, Thin: -1},
+		},
+		"initial parameters with zero density": {
+			logPost: func(params []float64) float64 { return math.Inf(-1) },
+			init:    []float64{0},
+			scale:   []float64{1},
+			cfg:     cfg,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := MetropolisHastings(test.logPost, test.init, test.scale, test.cfg)
+			require.NotNil(t, err)
+
+			_, err = SliceSample(test.logPost, test.init, test.scale, test.cfg)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestMetropolisHastings(t *testing.T) {
+	t.Run("samples independent normals", func(t *testing.T) {
+		means := []float64{-2, 5}
+		logPost := normalLogPosterior(means, []float64{1, 0.5})
+		cfg := &SamplerConfig{NSamples: 5000, BurnIn: 500, Thin: 2, Seed: 42}
+
+		samples, err := MetropolisHastings(logPost, []float64{0, 0}, []float64{1, 0.5}, cfg)
+		require.Nil(t, err)
+
+		assert.Equal(t, 5000, samples.Len())
+		assert.Equal(t, 2, samples.NParams())
+		rate := samples.AcceptanceRate()
+		assert.Greater(t, rate, 0.2)
+		assert.Less(t, rate, 0.9)
+
+		for i, mean := range means {
+			vals, err := samples.Param(i)
+			require.Nil(t, err)
+			assert.InDelta(t, mean, sampleMean(vals), 0.1)
+		}
+	})
+
+	t.Run("proposals outside the support are rejected", func(t *testing.T) {
+		// exponential distribution with rate 1
+		logPost := func(params []float64) float64 {
+			if params[0] < 0 {
+				return math.Inf(-1)
+			}
+			return -params[0]
+		}
+		cfg := &SamplerConfig{NSamples: 2000, BurnIn: 100, Seed: 7}
+
+		samples, err := MetropolisHastings(logPost, []float64{1}, []float64{1}, cfg)
+		require.Nil(t, err)
+
+		vals, err := samples.Param(0)
+		require.Nil(t, err)
+		for _, val := range vals {
+			require.GreaterOrEqual(t, val, 0.0)
+		}
+	})
+
+	t.Run("same seed gives same samples", func(t *testing.T) {
+		logPost := normalLogPosterior([]float64{0}, []float64{1})
+		cfg := &SamplerConfig{NSamples: 100, Seed: 3}
+
+		a, err := MetropolisHastings(logPost, []float64{0}, []float64{1}, cfg)
+		require.Nil(t, err)
+		b, err := MetropolisHastings(logPost, []float64{0}, []float64{1}, cfg)
+		require.Nil(t, err)
+
+		assert.Equal(t, a.draws, b.draws)
+	})
+}
+
+func TestSliceSample(t *testing.T) {
+	t.Run("samples independent normals", func(t *testing.T) {
+		means := []float64{3, -1}
+		logPost := normalLogPosterior(means, []float64{2, 1})
+		cfg := &SamplerConfig{NSamples: 3000, BurnIn: 100, Seed: 11}
+
+		samples, err := SliceSample(logPost, []float64{0, 0}, []float64{1, 1}, cfg)
+		require.Nil(t, err)
+
+		assert.Equal(t, 3000, samples.Len())
+		assert.Equal(t, 1.0, samples.AcceptanceRate())
+		for i, mean := range means {
+			vals, err := samples.Param(i)
+			require.Nil(t, err)
+			assert.InDelta(t, mean, sampleMean(vals), 0.15)
+		}
+	})
+
+	t.Run("errors when no point on the slice is found", func(t *testing.T) {
+		// a non-deterministic posterior that has zero density after the initial evaluation
+		calls := 0
+		logPost := func(params []float64) float64 {
+			calls++
+			if calls == 1 {
+				return 0
+			}
+			return math.Inf(-1)
+		}
+		cfg := &SamplerConfig{NSamples: 10, Seed: 11}
+
+		_, err := SliceSample(logPost, []float64{0}, []float64{1}, cfg)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSamplesParam(t *testing.T) {
+	samples := &Samples{draws: [][]float64{{1, 2}, {3, 4}}}
+
+	tests := map[string]struct {
+		idx       int
+		expected  []float64
+		shouldErr bool
+	}{
+		"negative index": {
+			idx:       -1,
+			shouldErr: true,
+		},
+		"index out of range": {
+			idx:       2,
+			shouldErr: true,
+		},
+		"valid index": {
+			idx:      1,
+			expected: []float64{2, 4},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			vals, err := samples.Param(test.idx)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, test.expected, vals)
+		})
+	}
+}
+
+func TestSamplesMakePmf(t *testing.T) {
+	samples := &Samples{draws: [][]float64{{1.1}, {0.9}, {2.2}, {1.1}}}
+
+	tests := map[string]struct {
+		binWidth float64
+		expected map[float64]float64
+	}{
+		"no binning": {
+			binWidth: 0,
+			expected: map[float64]float64{0.9: 0.25, 1.1: 0.5, 2.2: 0.25},
+		},
+		"binning": {
+			binWidth: 1,
+			expected: map[float64]float64{1: 0.75, 2: 0.25},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := samples.MakePmf(0, test.binWidth)
+
+			require.Nil(t, err)
+			assert.Equal(t, test.expected, probMap(p))
+
+			c, err := samples.MakeCdf(0, test.binWidth)
+			require.Nil(t, err)
+			median, err := c.Percentile(0.5)
+			require.Nil(t, err)
+			expectedMedian, err := p.Percentile(0.5)
+			require.Nil(t, err)
+			assert.Equal(t, expectedMedian, median)
+		})
+	}
+
+	t.Run("invalid index", func(t *testing.T) {
+		_, err := samples.MakePmf(1, 0)
+		require.NotNil(t, err)
+	})
+}
@@ -0,0 +1,171 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+)
+
+// ModelSet is a set of named competing models, each a Suite, updated with the same observations
+type ModelSet struct {
+	names       []string
+	models      map[string]*Suite
+	logPrior    map[string]float64
+	logEvidence map[string]float64
+	updated     bool // whether any observation has been applied
+}
+
+// NewModelSet creates a new ModelSet
+func NewModelSet() *ModelSet {
+	return &ModelSet{
+		names:       []string{},
+		models:      map[string]*Suite{},
+		logPrior:    map[string]float64{},
+		logEvidence: map[string]float64{},
+	}
+}
+
+// Add adds a copy of a suite as a model with the specified (unnormalized) prior probability;
+// models must be added before any observations
+func (m *ModelSet) Add(name string, s *Suite, prior float64) error {
+	if m.updated {
+		return fmt.Errorf("unable to add model [%s] after observations have been applied", name)
+	}
+	if _, ok := m.models[name]; ok {
+		return fmt.Errorf("model [%s] already exists", name)
+	}
+	if s == nil || len(s.vals) == 0 {
+		return fmt.Errorf("model [%s] must contain hypotheses", name)
+	}
+	if prior <= 0 || math.IsInf(prior, 0) || math.IsNaN(prior) {
+		return fmt.Errorf("prior probability [%v] of model [%s] must be positive", prior, name)
+	}
+
+	model := &Suite{Pmf: s.Copy(), parallelism: s.parallelism}
+	model.Normalize()
+	m.names = append(m.names, name)
+	m.models[name] = model
+	m.logPrior[name] = math.Log(prior)
+	m.logEvidence[name] = 0
+	return nil
+}
+
+// Names returns the names of the models in the order they were added
+func (m *ModelSet) Names() []string {
+	return append([]string{}, m.names...)
+}
+
+// Model returns a copy of the posterior of a model
+func (m *ModelSet) Model(name string) (*Suite, error) {
+	model, ok := m.models[name]
+	if !ok {
+		return nil, fmt.Errorf("model [%s] does not exist", name)
+	}
+	return &Suite{Pmf: model.Copy(), parallelism: model.parallelism}, nil
+}
+
+// Update updates each model based on an observation, accumulating the evidence for each model
+func (m *ModelSet) Update(ob SuiteObservation) {
+	m.updated = true
+	for _, name := range m.names {
+		model := m.models[name]
+		evidence := 0.0
+		for i, hypo := range model.vals {
+			model.probs[i] *= ob.GetLikelihood(hypo)
+			evidence += model.probs[i]
+		}
+		model.invalidate()
+		model.Normalize()
+		m.logEvidence[name] += math.Log(evidence)
+	}
+}
+
+// UpdateSet updates each model based on multiple observations
+func (m *ModelSet) UpdateSet(obs []SuiteObservation) {
+	for _, ob := range obs {
+		m.Update(ob)
+	}
+}
+
+// LogEvidence returns the log probability of the observations under a model
+func (m *ModelSet) LogEvidence(name string) (float64, error) {
+	logEvidence, ok := m.logEvidence[name]
+	if !ok {
+		return 0, fmt.Errorf("model [%s] does not exist", name)
+	}
+	return logEvidence, nil
+}
+
+// Posterior returns the posterior probability of each model
+func (m *ModelSet) Posterior() (*NamedPmf, error) {
+	if len(m.names) == 0 {
+		return NewNamedPmf(), fmt.Errorf("unable to compute posterior of empty model set")
+	}
+
+	// subtract the maximum log posterior before exponentiating to avoid underflow
+	maxLogPost := math.Inf(-1)
+	for _, name := range m.names {
+		maxLogPost = math.Max(maxLogPost, m.logPrior[name]+m.logEvidence[name])
+	}
+	if math.IsInf(maxLogPost, -1) {
+		return NewNamedPmf(), fmt.Errorf("unable to compute posterior: observations have zero probability under every model")
+	}
+
+	p := NewNamedPmf()
+	for _, name := range m.names {
+		p.Set(NewNamedPmfElement(name, math.Exp(m.logPrior[name]+m.logEvidence[name]-maxLogPost)))
+	}
+	p.Normalize()
+	return p, nil
+}
+
+// BayesFactor returns the ratio of the evidence for model a to the evidence for model b
+func (m *ModelSet) BayesFactor(a string, b string) (float64, error) {
+	logA, err := m.LogEvidence(a)
+	if err != nil {
+		return 0, err
+	}
+	logB, err := m.LogEvidence(b)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsInf(logA, -1) && math.IsInf(logB, -1) {
+		return 0, fmt.Errorf("unable to compute Bayes factor: observations have zero probability under both models")
+	}
+	return math.Exp(logA - logB), nil
+}
+
+// ModelAverage returns the posterior averaged over the models weighted by their posterior probabilities
+func (m *ModelSet) ModelAverage() (*Pmf, error) {
+	weights, err := m.Posterior()
+	if err != nil {
+		return NewPmf(), fmt.Errorf("unable to compute model average: %v", err)
+	}
+
+	avg := map[float64]float64{}
+	for name, weight := range weights.All() {
+		for val, prob := range m.models[name].All() {
+			avg[val] += weight * prob
+		}
+	}
+	return newPmfFromMap(avg), nil
+}
+
+// JeffreysScale interprets the strength of evidence for a model given its Bayes factor against
+// another model; a Bayes factor less than 1 is evidence for the other model and its strength is
+// given by JeffreysScale(1/bf)
+func JeffreysScale(bf float64) string {
+	switch {
+	case bf < 1:
+		return "negative"
+	case bf < math.Sqrt(10):
+		return "barely worth mentioning"
+	case bf < 10:
+		return "substantial"
+	case bf < math.Pow(10, 1.5):
+		return "strong"
+	case bf < 100:
+		return "very strong"
+	default:
+		return "decisive"
+	}
+}
@@ -0,0 +1,196 @@
+package prob
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupModelSet(t *testing.T) *ModelSet {
+	m := NewModelSet()
+	require.Nil(t, m.Add("small", NewSuite(NewPmfElement(2, 1), NewPmfElement(3, 1)), 1))
+	require.Nil(t, m.Add("large", NewSuite(NewPmfElement(3, 1), NewPmfElement(4, 1), NewPmfElement(5, 2)), 3))
+	return m
+}
+
+// modelEvidence computes the probability of the observations under a prior by enumeration
+func modelEvidence(prior *Suite, obs []SuiteObservation) float64 {
+	evidence := 0.0
+	for hypo, prob := range prior.All() {
+		like := prob
+		for _, ob := range obs {
+			like *= ob.GetLikelihood(hypo)
+		}
+		evidence += like
+	}
+	return evidence
+}
+
+func TestModelSetAdd(t *testing.T) {
+	tests := map[string]struct {
+		name      string
+		suite     *Suite
+		prior     float64
+		shouldErr bool
+	}{
+		"duplicate name": {
+			name:      "small",
+			suite:     NewSuite(NewPmfElement(1, 1)),
+			prior:     1,
+			shouldErr: true,
+		},
+		"nil suite": {
+			name:      "other",
+			suite:     nil,
+			prior:     1,
+			shouldErr: true,
+		},
+		"empty suite": {
+			name:      "other",
+			suite:     NewSuite(),
+			prior:     1,
+			shouldErr: true,
+		},
+		"zero prior": {
+			name:      "other",
+			suite:     NewSuite(NewPmfElement(1, 1)),
+			prior:     0,
+			shouldErr: true,
+		},
+		"valid": {
+			name:  "other",
+			suite: NewSuite(NewPmfElement(1, 1)),
+			prior: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := setupModelSet(t)
+			err := m.Add(test.name, test.suite, test.prior)
+			if test.shouldErr {
+				assert.NotNil(t, err)
+				assert.Equal(t, []string{"small", "large"}, m.Names())
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, []string{"small", "large", test.name}, m.Names())
+		})
+	}
+}
+
+func TestModelSetAddAfterUpdate(t *testing.T) {
+	m := setupModelSet(t)
+	m.Update(&suiteTestObservation{3})
+
+	// the observation is impossible under the model, which would otherwise be assigned no evidence
+	err := m.Add("impossible", NewSuite(NewPmfElement(1, 1)), 1)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"small", "large"}, m.Names())
+}
+
+func TestModelSetUpdate(t *testing.T) {
+	m := setupModelSet(t)
+	priorSmall, err := m.Model("small")
+	require.Nil(t, err)
+	priorLarge, err := m.Model("large")
+	require.Nil(t, err)
+
+	obs := []SuiteObservation{&suiteTestObservation{2}, &suiteTestObservation{3}}
+	m.UpdateSet(obs)
+
+	evSmall := modelEvidence(priorSmall, obs)
+	evLarge := modelEvidence(priorLarge, obs)
+
+	logEv, err := m.LogEvidence("small")
+	require.Nil(t, err)
+	assert.InDelta(t, math.Log(evSmall), logEv, float64EqualTol)
+	logEv, err = m.LogEvidence("large")
+	require.Nil(t, err)
+	assert.InDelta(t, math.Log(evLarge), logEv, float64EqualTol)
+	_, err = m.LogEvidence("missing")
+	assert.NotNil(t, err)
+
+	bf, err := m.BayesFactor("small", "large")
+	require.Nil(t, err)
+	assert.InDelta(t, evSmall/evLarge, bf, float64EqualTol)
+	_, err = m.BayesFactor("small", "missing")
+	assert.NotNil(t, err)
+
+	post, err := m.Posterior()
+	require.Nil(t, err)
+	total := evSmall*0.25 + evLarge*0.75
+	assert.InDelta(t, evSmall*0.25/total, post.Prob("small"), float64EqualTol)
+	assert.InDelta(t, evLarge*0.75/total, post.Prob("large"), float64EqualTol)
+
+	expectedSmall := &Suite{Pmf: priorSmall.Copy()}
+	expectedSmall.UpdateSet(obs)
+	small, err := m.Model("small")
+	require.Nil(t, err)
+	assert.InDeltaMapValues(t, probMap(expectedSmall), probMap(small), float64EqualTol)
+
+	// modifying the returned model does not modify the model set
+	small.Mult(3, 0)
+	small, err = m.Model("small")
+	require.Nil(t, err)
+	assert.InDeltaMapValues(t, probMap(expectedSmall), probMap(small), float64EqualTol)
+
+	avg, err := m.ModelAverage()
+	require.Nil(t, err)
+	expectedLarge := &Suite{Pmf: priorLarge.Copy()}
+	expectedLarge.UpdateSet(obs)
+	for val, prob := range avg.All() {
+		expected := post.Prob("small")*expectedSmall.Prob(val) + post.Prob("large")*expectedLarge.Prob(val)
+		assert.InDelta(t, expected, prob, float64EqualTol)
+	}
+	assert.InDelta(t, 1, getSum(probMap(avg)), float64EqualTol)
+}
+
+func TestModelSetZeroEvidence(t *testing.T) {
+	m := setupModelSet(t)
+	m.Update(&suiteTestObservation{5})
+
+	post, err := m.Posterior()
+	require.Nil(t, err)
+	assert.Equal(t, 0.0, post.Prob("small"))
+	assert.Equal(t, 1.0, post.Prob("large"))
+
+	bf, err := m.BayesFactor("small", "large")
+	require.Nil(t, err)
+	assert.Equal(t, 0.0, bf)
+
+	m.Update(&suiteTestObservation{6})
+	_, err = m.Posterior()
+	assert.NotNil(t, err)
+	_, err = m.ModelAverage()
+	assert.NotNil(t, err)
+	_, err = m.BayesFactor("small", "large")
+	assert.NotNil(t, err)
+}
+
+func TestModelSetPosteriorEmpty(t *testing.T) {
+	_, err := NewModelSet().Posterior()
+	assert.NotNil(t, err)
+}
+
+func TestJeffreysScale(t *testing.T) {
+	tests := map[string]struct {
+		bf       float64
+		expected string
+	}{
+		"negative":                {bf: 0.5, expected: "negative"},
+		"barely worth mentioning": {bf: 2, expected: "barely worth mentioning"},
+		"substantial":             {bf: 5, expected: "substantial"},
+		"strong":                  {bf: 20, expected: "strong"},
+		"very strong":             {bf: 50, expected: "very strong"},
+		"decisive":                {bf: 150, expected: "decisive"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, JeffreysScale(test.bf))
+		})
+	}
+}
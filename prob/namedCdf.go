@@ -0,0 +1,62 @@
+package prob
+
+import (
+	"fmt"
+)
+
+// NamedCdf is a cumulative distribution function over ordered names
+type NamedCdf struct {
+	cdf        *Cdf
+	rankToName map[float64]string
+}
+
+// NewNamedCdf creates a new NamedCdf from probabilities of names and an ordering of the names;
+// every name with a probability must appear in the ordering
+func NewNamedCdf(p map[string]float64, order []string) (c *NamedCdf, err error) {
+	rankToName := map[float64]string{}
+	rankProb := map[float64]float64{}
+	seen := map[string]bool{}
+	for i, name := range order {
+		prob, ok := p[name]
+		if !ok || seen[name] {
+			// names without a probability are permitted in the ordering; repeated names are ranked first seen
+			continue
+		}
+		seen[name] = true
+		rank := float64(i)
+		rankToName[rank] = name
+		rankProb[rank] = prob
+	}
+	if len(rankProb) != len(p) {
+		return c, fmt.Errorf("cannot compute cdf: ordering does not include every name")
+	}
+
+	cdf, err := NewCdf(rankProb)
+	if err != nil {
+		return c, err
+	}
+
+	c = &NamedCdf{
+		cdf:        cdf,
+		rankToName: rankToName,
+	}
+	return c, nil
+}
+
+// Percentile computes the specified percentile of the distribution
+func (c *NamedCdf) Percentile(p float64) (string, error) {
+	rank, err := c.cdf.Percentile(p)
+	if err != nil {
+		return "", err
+	}
+	return c.rankToName[rank], nil
+}
+
+// CredibleInterval computes the lower and upper bounds of a credible interval of specified length
+func (c *NamedCdf) CredibleInterval(l float64) (string, string, error) {
+	lower, upper, err := CredibleInterval(c.cdf, l)
+	if err != nil {
+		return "", "", err
+	}
+	return c.rankToName[lower], c.rankToName[upper], nil
+}
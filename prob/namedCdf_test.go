@@ -0,0 +1,118 @@
+package prob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNamedCdf(t *testing.T) {
+	tests := map[string]struct {
+		prob      map[string]float64
+		order     []string
+		shouldErr bool
+	}{
+		"empty input": {
+			prob:      map[string]float64{},
+			order:     []string{},
+			shouldErr: true,
+		},
+		"ordering missing a name": {
+			prob:      map[string]float64{"low": 0.5, "high": 0.5},
+			order:     []string{"low"},
+			shouldErr: true,
+		},
+		"ordering with repeated name": {
+			prob:      map[string]float64{"low": 0.5, "high": 0.5},
+			order:     []string{"low", "low"},
+			shouldErr: true,
+		},
+		"ordering with extra name": {
+			prob:      map[string]float64{"low": 0.5, "high": 0.5},
+			order:     []string{"low", "medium", "high"},
+			shouldErr: false,
+		},
+		"all zero probabilities": {
+			prob:      map[string]float64{"low": 0, "high": 0},
+			order:     []string{"low", "high"},
+			shouldErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewNamedCdf(test.prob, test.order)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestNamedCdfPercentile(t *testing.T) {
+	prob := map[string]float64{"low": 0.2, "medium": 0.3, "high": 0.4, "extreme": 0.1}
+	order := []string{"low", "medium", "high", "extreme"}
+
+	tests := map[string]struct {
+		percentile float64
+		expected   string
+		shouldErr  bool
+	}{
+		"percentile less than 0": {
+			percentile: -0.5,
+			shouldErr:  true,
+		},
+		"percentile 0": {
+			percentile: 0,
+			expected:   "low",
+		},
+		"percentile 0.5": {
+			percentile: 0.5,
+			expected:   "medium",
+		},
+		"percentile 0.51": {
+			percentile: 0.51,
+			expected:   "high",
+		},
+		"percentile 1": {
+			percentile: 1,
+			expected:   "extreme",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewNamedCdf(prob, order)
+			require.Nil(t, err)
+
+			res, err := c.Percentile(test.percentile)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, test.expected, res)
+		})
+	}
+}
+
+func TestNamedCdfCredibleInterval(t *testing.T) {
+	t.Run("credible interval", func(t *testing.T) {
+		c, err := NewNamedCdf(
+			map[string]float64{"low": 0.125, "medium": 0.5, "high": 0.25, "extreme": 0.125},
+			[]string{"low", "medium", "high", "extreme"},
+		)
+		require.Nil(t, err)
+
+		lower, upper, err := c.CredibleInterval(75)
+
+		require.Nil(t, err)
+		assert.Equal(t, "low", lower)
+		assert.Equal(t, "high", upper)
+	})
+}
@@ -2,12 +2,14 @@ package prob
 
 import (
 	"fmt"
+	"iter"
+	"sort"
 )
 
 // NamedPmfElement is a discrete element in a NamedPmf
 type NamedPmfElement struct {
-	Name string
-	Prob float64
+	Name string  `json:"name"`
+	Prob float64 `json:"prob"`
 }
 
 // NewNamedPmfElement creates a new NamedPmfElement
@@ -23,6 +25,7 @@ type NamedPmf struct {
 	pmf       *Pmf
 	nameToIdx map[string]float64
 	nextIdx   float64
+	order     []string
 }
 
 // NewNamedPmf creates a new Pmf
@@ -34,11 +37,45 @@ func NewNamedPmf() *NamedPmf {
 	}
 }
 
-// Set sets the value of an element
+// Set sets the value of an element, replacing the probability of an existing name
 func (p *NamedPmf) Set(elem *NamedPmfElement) {
-	p.pmf.Set(NewPmfElement(p.nextIdx, elem.Prob))
-	p.nameToIdx[elem.Name] = p.nextIdx
-	p.nextIdx++
+	idx, ok := p.nameToIdx[elem.Name]
+	if !ok {
+		idx = p.nextIdx
+		p.nameToIdx[elem.Name] = idx
+		p.nextIdx++
+	}
+	p.pmf.Set(NewPmfElement(idx, elem.Prob))
+}
+
+// Remove removes an element
+func (p *NamedPmf) Remove(name string) {
+	idx, ok := p.nameToIdx[name]
+	if !ok {
+		return
+	}
+	p.pmf.remove(idx)
+	delete(p.nameToIdx, name)
+}
+
+// Incr increments the probability associated with an element by the specified amount,
+// adding the element if it does not exist
+func (p *NamedPmf) Incr(name string, amount float64) {
+	p.Set(NewNamedPmfElement(name, p.Prob(name)+amount))
+}
+
+// Len returns the number of elements
+func (p *NamedPmf) Len() int {
+	return len(p.nameToIdx)
+}
+
+// Total returns the sum of the probabilities of all elements
+func (p *NamedPmf) Total() float64 {
+	total := 0.0
+	for _, prob := range p.pmf.probs {
+		total += prob
+	}
+	return total
 }
 
 // Normalize normalizes the values of the Pmf to sum to 1
@@ -66,23 +103,112 @@ func (p *NamedPmf) Prob(name string) float64 {
 	return p.pmf.Prob(idx)
 }
 
+// Values returns the names of the NamedPmf in insertion order
+func (p *NamedPmf) Values() []string {
+	names := make([]string, 0, len(p.nameToIdx))
+	for name := range p.nameToIdx {
+		names = append(names, name)
+	}
+	// indices are assigned in increasing order as names are set
+	sort.Slice(names, func(i, j int) bool {
+		return p.nameToIdx[names[i]] < p.nameToIdx[names[j]]
+	})
+	return names
+}
+
+// Names returns the names of the NamedPmf in insertion order; it is equivalent to Values
+func (p *NamedPmf) Names() []string {
+	return p.Values()
+}
+
+// Items returns the elements of the NamedPmf in insertion order
+func (p *NamedPmf) Items() []*NamedPmfElement {
+	items := make([]*NamedPmfElement, 0, len(p.nameToIdx))
+	for _, name := range p.Values() {
+		items = append(items, NewNamedPmfElement(name, p.Prob(name)))
+	}
+	return items
+}
+
+// All returns an iterator over the names and probabilities of the NamedPmf in insertion order
+func (p *NamedPmf) All() iter.Seq2[string, float64] {
+	return func(yield func(string, float64) bool) {
+		for _, name := range p.Values() {
+			if !yield(name, p.Prob(name)) {
+				return
+			}
+		}
+	}
+}
+
 // Print prints the Pmf
 func (p *NamedPmf) Print() {
 	border := "----------"
 	fmt.Println(border)
-	for name := range p.nameToIdx {
-		pr := p.Prob(name)
+	for name, pr := range p.All() {
 		fmt.Printf("%s: %0.2f\n", name, pr)
 	}
 	fmt.Println(border)
 	fmt.Println()
 }
 
+// SetOrder sets an ordering of names used to compute a Cdf, percentiles and credible intervals;
+// names are otherwise ordered by insertion
+func (p *NamedPmf) SetOrder(names ...string) {
+	p.order = append([]string{}, names...)
+}
+
+// MakeCdf transforms a NamedPmf to a NamedCdf
+func (p *NamedPmf) MakeCdf() (*NamedCdf, error) {
+	order := p.order
+	if order == nil {
+		order = p.Values()
+	}
+
+	prob := map[string]float64{}
+	for name, pr := range p.All() {
+		prob[name] = pr
+	}
+	return NewNamedCdf(prob, order)
+}
+
+// Percentile computes the specified percentile of the distribution
+func (p *NamedPmf) Percentile(percentile float64) (string, error) {
+	c, err := p.MakeCdf()
+	if err != nil {
+		return "", fmt.Errorf("cannot compute percentile: %v", err)
+	}
+	return c.Percentile(percentile)
+}
+
+// CredibleInterval computes the lower and upper bounds of a credible interval of specified length
+func (p *NamedPmf) CredibleInterval(l float64) (string, string, error) {
+	c, err := p.MakeCdf()
+	if err != nil {
+		return "", "", fmt.Errorf("cannot compute credible interval: %v", err)
+	}
+	return c.CredibleInterval(l)
+}
+
+// ToPmf transforms a NamedPmf to a Pmf using a function mapping each name to a value;
+// probabilities of names mapping to the same value are summed
+func (p *NamedPmf) ToPmf(f func(string) (float64, error)) (*Pmf, error) {
+	prob := map[float64]float64{}
+	for name, pr := range p.All() {
+		val, err := f(name)
+		if err != nil {
+			return NewPmf(), fmt.Errorf("unable to map name [%s] to value: %v", name, err)
+		}
+		prob[val] += pr
+	}
+	return newPmfFromMap(prob), nil
+}
+
 // MaximumLikelihood returns the value with the highest probability
 func (p *NamedPmf) MaximumLikelihood() (maxVal string, err error) {
 	maxProb := 0.0
-	for name, idx := range p.nameToIdx {
-		prob := p.pmf.prob[idx]
+	// iterate in insertion order so that ties resolve to the earliest name
+	for name, prob := range p.All() {
 		if prob > maxProb {
 			maxProb = prob
 			maxVal = name
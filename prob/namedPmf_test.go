@@ -1,6 +1,7 @@
 package prob
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,8 +47,8 @@ func TestNamedSet(t *testing.T) {
 
 			for _, elem := range test.elements {
 				require.Contains(t, p.nameToIdx, elem.Name)
-				require.Contains(t, p.pmf.prob, p.nameToIdx[elem.Name])
-				assert.Equal(t, elem.Prob, p.pmf.prob[p.nameToIdx[elem.Name]])
+				require.Contains(t, probMap(p.pmf), p.nameToIdx[elem.Name])
+				assert.Equal(t, elem.Prob, probMap(p.pmf)[p.nameToIdx[elem.Name]])
 			}
 		})
 	}
@@ -82,21 +83,21 @@ func TestNamedMult(t *testing.T) {
 			p := setupNamedPmf(test.elements)
 
 			idx, found := p.nameToIdx[test.val]
-			origProb, foundInProb := p.pmf.prob[idx]
+			origProb, foundInProb := probMap(p.pmf)[idx]
 
 			p.Mult(test.val, test.multFactor)
 
 			// test probability of specified element correctly multiplied
 			if found {
 				require.True(t, foundInProb)
-				assert.Equal(t, origProb*test.multFactor, p.pmf.prob[idx])
+				assert.Equal(t, origProb*test.multFactor, probMap(p.pmf)[idx])
 			}
 			// test other probabilities are unchanged
 			for _, element := range test.elements {
 				if element.Name == test.val {
 					continue
 				}
-				assert.Equal(t, element.Prob, p.pmf.prob[p.nameToIdx[element.Name]])
+				assert.Equal(t, element.Prob, probMap(p.pmf)[p.nameToIdx[element.Name]])
 			}
 		})
 	}
@@ -189,3 +190,377 @@ func TestNamedMaximumLikelihood(t *testing.T) {
 		})
 	}
 }
+
+func TestNamedValues(t *testing.T) {
+	tests := map[string]struct {
+		elements []*NamedPmfElement
+		expected []string
+	}{
+		"empty Pmf": {
+			elements: []*NamedPmfElement{},
+			expected: []string{},
+		},
+		"multiple elements": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("c", 0.2),
+				NewNamedPmfElement("a", 0.5),
+				NewNamedPmfElement("b", 0.3),
+			},
+			expected: []string{"c", "a", "b"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupNamedPmf(test.elements)
+
+			assert.Equal(t, test.expected, p.Values())
+		})
+	}
+}
+
+func TestNamedItems(t *testing.T) {
+	t.Run("items in insertion order", func(t *testing.T) {
+		elements := []*NamedPmfElement{
+			NewNamedPmfElement("c", 0.2),
+			NewNamedPmfElement("a", 0.5),
+			NewNamedPmfElement("b", 0.3),
+		}
+		p := setupNamedPmf(elements)
+
+		assert.Equal(t, elements, p.Items())
+	})
+}
+
+func TestNamedAll(t *testing.T) {
+	t.Run("iterates in insertion order", func(t *testing.T) {
+		p := setupNamedPmf([]*NamedPmfElement{
+			NewNamedPmfElement("c", 0.2),
+			NewNamedPmfElement("a", 0.5),
+			NewNamedPmfElement("b", 0.3),
+		})
+
+		names := []string{}
+		probs := []float64{}
+		for name, prob := range p.All() {
+			names = append(names, name)
+			probs = append(probs, prob)
+		}
+
+		assert.Equal(t, []string{"c", "a", "b"}, names)
+		assert.Equal(t, []float64{0.2, 0.5, 0.3}, probs)
+	})
+}
+
+func TestNamedSetExistingName(t *testing.T) {
+	t.Run("re-setting a name replaces its probability", func(t *testing.T) {
+		p := setupNamedPmf([]*NamedPmfElement{
+			NewNamedPmfElement("a", 1),
+			NewNamedPmfElement("b", 1),
+			NewNamedPmfElement("a", 3),
+		})
+
+		assert.Equal(t, 2, p.Len())
+		assert.Len(t, probMap(p.pmf), 2)
+		assert.Equal(t, []string{"a", "b"}, p.Names())
+		assert.Equal(t, 3.0, p.Prob("a"))
+
+		p.Normalize()
+		assert.Equal(t, 0.75, p.Prob("a"))
+		assert.Equal(t, 0.25, p.Prob("b"))
+	})
+}
+
+func TestNamedRemove(t *testing.T) {
+	tests := map[string]struct {
+		elements      []*NamedPmfElement
+		name          string
+		expectedNames []string
+	}{
+		"element not in Pmf": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("a", 0.5),
+				NewNamedPmfElement("b", 0.5),
+			},
+			name:          "c",
+			expectedNames: []string{"a", "b"},
+		},
+		"element in Pmf": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("a", 0.5),
+				NewNamedPmfElement("b", 0.5),
+			},
+			name:          "a",
+			expectedNames: []string{"b"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupNamedPmf(test.elements)
+
+			p.Remove(test.name)
+
+			assert.Equal(t, test.expectedNames, p.Names())
+			assert.Equal(t, len(test.expectedNames), p.Len())
+			assert.Len(t, probMap(p.pmf), len(test.expectedNames))
+			assert.Equal(t, 0.0, p.Prob(test.name))
+		})
+	}
+
+	t.Run("removed name is re-added at the end", func(t *testing.T) {
+		p := setupNamedPmf([]*NamedPmfElement{
+			NewNamedPmfElement("a", 0.5),
+			NewNamedPmfElement("b", 0.5),
+		})
+
+		p.Remove("a")
+		p.Set(NewNamedPmfElement("a", 0.25))
+
+		assert.Equal(t, []string{"b", "a"}, p.Names())
+		assert.Equal(t, 0.25, p.Prob("a"))
+	})
+}
+
+func TestNamedIncr(t *testing.T) {
+	tests := map[string]struct {
+		elements     []*NamedPmfElement
+		name         string
+		amount       float64
+		expectedProb float64
+		expectedLen  int
+	}{
+		"element not in Pmf": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("a", 1),
+			},
+			name:         "b",
+			amount:       2,
+			expectedProb: 2,
+			expectedLen:  2,
+		},
+		"element in Pmf": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("a", 1),
+			},
+			name:         "a",
+			amount:       2,
+			expectedProb: 3,
+			expectedLen:  1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupNamedPmf(test.elements)
+
+			p.Incr(test.name, test.amount)
+
+			assert.Equal(t, test.expectedProb, p.Prob(test.name))
+			assert.Equal(t, test.expectedLen, p.Len())
+		})
+	}
+}
+
+func TestNamedTotal(t *testing.T) {
+	tests := map[string]struct {
+		elements []*NamedPmfElement
+		expected float64
+	}{
+		"empty Pmf": {
+			elements: []*NamedPmfElement{},
+			expected: 0,
+		},
+		"multiple elements": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("a", 1),
+				NewNamedPmfElement("b", 2.5),
+			},
+			expected: 3.5,
+		},
+		"multiple elements with repeated name": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("a", 1),
+				NewNamedPmfElement("b", 2.5),
+				NewNamedPmfElement("a", 2),
+			},
+			expected: 4.5,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupNamedPmf(test.elements)
+
+			assert.Equal(t, test.expected, p.Total())
+		})
+	}
+}
+
+func TestNamedMakeCdf(t *testing.T) {
+	elements := []*NamedPmfElement{
+		NewNamedPmfElement("high", 0.2),
+		NewNamedPmfElement("low", 0.5),
+		NewNamedPmfElement("medium", 0.3),
+	}
+
+	tests := map[string]struct {
+		order          []string
+		expectedMedian string
+		shouldErr      bool
+	}{
+		"insertion order": {
+			order:          nil,
+			expectedMedian: "low",
+		},
+		"caller-supplied order": {
+			order:          []string{"low", "medium", "high"},
+			expectedMedian: "low",
+		},
+		"reversed caller-supplied order": {
+			order:          []string{"high", "medium", "low"},
+			expectedMedian: "medium",
+		},
+		"caller-supplied order missing a name": {
+			order:     []string{"low", "high"},
+			shouldErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupNamedPmf(elements)
+			if test.order != nil {
+				p.SetOrder(test.order...)
+			}
+
+			c, err := p.MakeCdf()
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			median, err := c.Percentile(0.5)
+			require.Nil(t, err)
+			assert.Equal(t, test.expectedMedian, median)
+		})
+	}
+}
+
+func TestNamedPercentile(t *testing.T) {
+	tests := map[string]struct {
+		percentile float64
+		expected   string
+		shouldErr  bool
+	}{
+		"percentile greater than 1": {
+			percentile: 2,
+			shouldErr:  true,
+		},
+		"percentile 0.2": {
+			percentile: 0.2,
+			expected:   "low",
+		},
+		"percentile 0.7": {
+			percentile: 0.7,
+			expected:   "medium",
+		},
+		"percentile 0.9": {
+			percentile: 0.9,
+			expected:   "high",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupNamedPmf([]*NamedPmfElement{
+				NewNamedPmfElement("high", 0.2),
+				NewNamedPmfElement("medium", 0.5),
+				NewNamedPmfElement("low", 0.3),
+			})
+			p.SetOrder("low", "medium", "high")
+
+			res, err := p.Percentile(test.percentile)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, test.expected, res)
+		})
+	}
+}
+
+func TestNamedCredibleInterval(t *testing.T) {
+	t.Run("credible interval", func(t *testing.T) {
+		p := setupNamedPmf([]*NamedPmfElement{
+			NewNamedPmfElement("a", 0.125),
+			NewNamedPmfElement("b", 0.5),
+			NewNamedPmfElement("c", 0.25),
+			NewNamedPmfElement("d", 0.125),
+		})
+
+		lower, upper, err := p.CredibleInterval(75)
+
+		require.Nil(t, err)
+		assert.Equal(t, "a", lower)
+		assert.Equal(t, "c", upper)
+	})
+}
+
+func TestNamedToPmf(t *testing.T) {
+	tests := map[string]struct {
+		elements  []*NamedPmfElement
+		f         func(string) (float64, error)
+		expected  map[float64]float64
+		shouldErr bool
+	}{
+		"mapping error": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("a", 1),
+			},
+			f: func(name string) (float64, error) {
+				return 0, fmt.Errorf("unknown name")
+			},
+			shouldErr: true,
+		},
+		"one to one": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("one", 0.25),
+				NewNamedPmfElement("two", 0.75),
+			},
+			f: func(name string) (float64, error) {
+				return map[string]float64{"one": 1, "two": 2}[name], nil
+			},
+			expected: map[float64]float64{1: 0.25, 2: 0.75},
+		},
+		"collisions are merged": {
+			elements: []*NamedPmfElement{
+				NewNamedPmfElement("a", 0.25),
+				NewNamedPmfElement("A", 0.5),
+				NewNamedPmfElement("b", 0.25),
+			},
+			f: func(name string) (float64, error) {
+				return map[string]float64{"a": 1, "A": 1, "b": 2}[name], nil
+			},
+			expected: map[float64]float64{1: 0.75, 2: 0.25},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupNamedPmf(test.elements)
+
+			pmf, err := p.ToPmf(test.f)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, test.expected, probMap(pmf))
+		})
+	}
+}
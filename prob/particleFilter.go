@@ -0,0 +1,221 @@
+package prob
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// defaultESSThreshold is the fraction of particles below which the effective sample size triggers
+// resampling by default
+const defaultESSThreshold = 0.5
+
+// Transition draws the next hidden state given the current hidden state
+type Transition func(state float64, rng *rand.Rand) float64
+
+// ResampleMethod is a method of resampling particles in proportion to their weights
+type ResampleMethod int
+
+const (
+	// SystematicResampling resamples using a single random offset and evenly spaced points
+	SystematicResampling ResampleMethod = iota
+	// MultinomialResampling resamples using independent draws
+	MultinomialResampling
+)
+
+// ParticleFilterConfig contains the settings of a ParticleFilter
+type ParticleFilterConfig struct {
+	NParticles   int            // number of particles
+	Resampling   ResampleMethod // method used to resample particles
+	ESSThreshold float64        // resample when the effective sample size falls below this fraction of particles; 0 is equivalent to 0.5
+	BinWidth     float64        // if positive, states are rounded to the nearest multiple of BinWidth in each Pmf
+	Seed         int64          // seed for random number generation; 0 seeds from the current time
+}
+
+func (c *ParticleFilterConfig) validate() error {
+	if c == nil {
+		return fmt.Errorf("config is required")
+	}
+	if c.NParticles <= 0 {
+		return fmt.Errorf("number of particles [%d] must be positive", c.NParticles)
+	}
+	if c.Resampling != SystematicResampling && c.Resampling != MultinomialResampling {
+		return fmt.Errorf("unknown resampling method [%d]", c.Resampling)
+	}
+	if c.ESSThreshold < 0 || c.ESSThreshold > 1 {
+		return fmt.Errorf("effective sample size threshold [%v] is outside of required range [0, 1]", c.ESSThreshold)
+	}
+	return nil
+}
+
+// ParticleFilter tracks a time-varying hidden state using sequential Monte Carlo
+type ParticleFilter struct {
+	particles  []float64
+	weights    []float64
+	transition Transition
+	cfg        ParticleFilterConfig
+	rng        *rand.Rand
+}
+
+// NewParticleFilter creates a new ParticleFilter with particles drawn from the prior
+func NewParticleFilter(prior *Pmf, transition Transition, cfg *ParticleFilterConfig) (f *ParticleFilter, err error) {
+	if prior == nil {
+		return f, fmt.Errorf("unable to create particle filter: prior is required")
+	}
+	if transition == nil {
+		return f, fmt.Errorf("unable to create particle filter: transition is required")
+	}
+	if err := cfg.validate(); err != nil {
+		return f, fmt.Errorf("unable to create particle filter: %v", err)
+	}
+
+	f = &ParticleFilter{
+		particles:  make([]float64, cfg.NParticles),
+		weights:    make([]float64, cfg.NParticles),
+		transition: transition,
+		cfg:        *cfg,
+		rng:        newRand(cfg.Seed),
+	}
+	if f.cfg.ESSThreshold == 0 {
+		f.cfg.ESSThreshold = defaultESSThreshold
+	}
+
+	for i := range f.particles {
+		f.particles[i], err = prior.Random(f.rng)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create particle filter: %v", err)
+		}
+		f.weights[i] = 1 / float64(cfg.NParticles)
+	}
+	return f, nil
+}
+
+// Step advances each particle with the transition model, weights the particles by the likelihood
+// of the observation, resamples if the effective sample size is below threshold, and returns the
+// filtered distribution of the hidden state; the particles are unchanged if the step fails
+func (f *ParticleFilter) Step(ob SuiteObservation) (*Pmf, error) {
+	particles := make([]float64, len(f.particles))
+	weights := make([]float64, len(f.weights))
+	sum := 0.0
+	for i, state := range f.particles {
+		particles[i] = f.transition(state, f.rng)
+		weights[i] = f.weights[i] * ob.GetLikelihood(particles[i])
+		sum += weights[i]
+	}
+	if !(sum > 0) || math.IsInf(sum, 0) {
+		return nil, fmt.Errorf("unable to update particle filter: observation has zero likelihood for all particles")
+	}
+	for i := range weights {
+		weights[i] /= sum
+	}
+	f.particles, f.weights = particles, weights
+
+	// compute the distribution before resampling, which adds noise without changing the distribution
+	p := f.Pmf()
+	if f.EffectiveSampleSize() < f.cfg.ESSThreshold*float64(len(f.particles)) {
+		f.resample()
+	}
+	return p, nil
+}
+
+// Filter applies Step to each observation in order and returns the filtered distribution of the
+// hidden state at each step
+func (f *ParticleFilter) Filter(obs []SuiteObservation) ([]*Pmf, error) {
+	pmfs := make([]*Pmf, 0, len(obs))
+	for t, ob := range obs {
+		p, err := f.Step(ob)
+		if err != nil {
+			return pmfs, fmt.Errorf("step %d: %v", t, err)
+		}
+		pmfs = append(pmfs, p)
+	}
+	return pmfs, nil
+}
+
+// Pmf returns the current distribution of the hidden state
+func (f *ParticleFilter) Pmf() *Pmf {
+	prob := map[float64]float64{}
+	for i, state := range f.particles {
+		if f.cfg.BinWidth > 0 {
+			state = math.Round(state/f.cfg.BinWidth) * f.cfg.BinWidth
+		}
+		prob[state] += f.weights[i]
+	}
+	p := newPmfFromMap(prob)
+	p.Normalize()
+	return p
+}
+
+// EffectiveSampleSize returns the effective number of particles given their weights
+func (f *ParticleFilter) EffectiveSampleSize() float64 {
+	sumSq := 0.0
+	for _, w := range f.weights {
+		sumSq += w * w
+	}
+	if sumSq == 0 {
+		return 0
+	}
+	return 1 / sumSq
+}
+
+// resample replaces the particles with draws in proportion to their weights and resets the weights
+func (f *ParticleFilter) resample() {
+	var idxs []int
+	switch f.cfg.Resampling {
+	case MultinomialResampling:
+		idxs = multinomialResample(f.weights, f.rng)
+	default:
+		idxs = systematicResample(f.weights, f.rng)
+	}
+
+	resampled := make([]float64, len(idxs))
+	for i, idx := range idxs {
+		resampled[i] = f.particles[idx]
+	}
+	f.particles = resampled
+	for i := range f.weights {
+		f.weights[i] = 1 / float64(len(f.weights))
+	}
+}
+
+// systematicResample returns indices of normalized weights selected at evenly spaced points
+// with a single random offset
+func systematicResample(weights []float64, rng *rand.Rand) []int {
+	n := len(weights)
+	idxs := make([]int, n)
+	offset := rng.Float64()
+	cumsum := weights[0]
+	j := 0
+	for i := 0; i < n; i++ {
+		u := (float64(i) + offset) / float64(n)
+		for u > cumsum && j < n-1 {
+			j++
+			cumsum += weights[j]
+		}
+		idxs[i] = j
+	}
+	return idxs
+}
+
+// multinomialResample returns indices of normalized weights selected by independent draws
+func multinomialResample(weights []float64, rng *rand.Rand) []int {
+	n := len(weights)
+	cumsum := make([]float64, n)
+	total := 0.0
+	for i, w := range weights {
+		total += w
+		cumsum[i] = total
+	}
+
+	idxs := make([]int, n)
+	for i := range idxs {
+		u := rng.Float64() * total
+		j := sort.Search(n, func(j int) bool {
+			return cumsum[j] > u
+		})
+		// guard against rounding in the final cumulative sum
+		idxs[i] = min(j, n-1)
+	}
+	return idxs
+}
@@ -0,0 +1,244 @@
+package prob
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gaussianObservation is a noisy measurement of the hidden state
+type gaussianObservation struct {
+	val float64
+	sd  float64
+}
+
+func (o *gaussianObservation) GetLikelihood(hypo float64) float64 {
+	z := (hypo - o.val) / o.sd
+	return math.Exp(-0.5 * z * z)
+}
+
+func randomWalk(sd float64) Transition {
+	return func(state float64, rng *rand.Rand) float64 {
+		return state + sd*rng.NormFloat64()
+	}
+}
+
+func staticTransition(state float64, rng *rand.Rand) float64 {
+	return state
+}
+
+func TestNewParticleFilter(t *testing.T) {
+	prior := NewSuite(Uniform(NewBound(0, 10))...).Pmf
+
+	tests := map[string]struct {
+		prior      *Pmf
+		transition Transition
+		cfg        *ParticleFilterConfig
+		shouldErr  bool
+	}{
+		"nil prior": {
+			prior:      nil,
+			transition: staticTransition,
+			cfg:        &ParticleFilterConfig{NParticles: 10},
+			shouldErr:  true,
+		},
+		"empty prior": {
+			prior:      NewPmf(),
+			transition: staticTransition,
+			cfg:        &ParticleFilterConfig{NParticles: 10},
+			shouldErr:  true,
+		},
+		"nil transition": {
+			prior:      prior,
+			transition: nil,
+			cfg:        &ParticleFilterConfig{NParticles: 10},
+			shouldErr:  true,
+		},
+		"nil config": {
+			prior:      prior,
+			transition: staticTransition,
+			cfg:        nil,
+			shouldErr:  true,
+		},
+		"no particles": {
+			prior:      prior,
+			transition: staticTransition,
+			cfg:        &ParticleFilterConfig{NParticles: 0},
+			shouldErr:  true,
+		},
+		"unknown resampling method": {
+			prior:      prior,
+			transition: staticTransition,
+			cfg:        &ParticleFilterConfig{NParticles: 10, Resampling: 5},
+			shouldErr:  true,
+		},
+		"threshold out of range": {
+			prior:      prior,
+			transition: staticTransition,
+			cfg:        &ParticleFilterConfig{NParticles: 10, ESSThreshold: 1.5},
+			shouldErr:  true,
+		},
+		"valid": {
+			prior:      prior,
+			transition: staticTransition,
+			cfg:        &ParticleFilterConfig{NParticles: 10, Seed: 1},
+			shouldErr:  false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewParticleFilter(test.prior, test.transition, test.cfg)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Len(t, f.particles, test.cfg.NParticles)
+			assert.Equal(t, defaultESSThreshold, f.cfg.ESSThreshold)
+			assert.InDelta(t, float64(test.cfg.NParticles), f.EffectiveSampleSize(), float64EqualTol)
+		})
+	}
+}
+
+func TestParticleFilterStaticState(t *testing.T) {
+	t.Run("static state approximates suite posterior", func(t *testing.T) {
+		prior := NewSuite(Uniform(NewBound(1, 20))...)
+		obs := []SuiteObservation{
+			&suiteTestObservation{6},
+			&suiteTestObservation{8},
+			&suiteTestObservation{7},
+		}
+
+		f, err := NewParticleFilter(prior.Pmf, staticTransition, &ParticleFilterConfig{NParticles: 20000, Seed: 1})
+		require.Nil(t, err)
+		pmfs, err := f.Filter(obs)
+		require.Nil(t, err)
+		require.Len(t, pmfs, len(obs))
+
+		for _, ob := range obs {
+			prior.Update(ob)
+		}
+		for val, prob := range prior.All() {
+			assert.InDelta(t, prob, pmfs[len(pmfs)-1].Prob(val), 0.02)
+		}
+	})
+}
+
+func TestParticleFilterTracking(t *testing.T) {
+	methods := map[string]ResampleMethod{
+		"systematic":  SystematicResampling,
+		"multinomial": MultinomialResampling,
+	}
+
+	for name, method := range methods {
+		t.Run(name, func(t *testing.T) {
+			// hidden state drifts upward by 1 each step
+			truth := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+			obs := []SuiteObservation{}
+			for _, state := range truth {
+				obs = append(obs, &gaussianObservation{val: state, sd: 0.5})
+			}
+
+			prior := NewSuite(Uniform(NewBound(-5, 5))...)
+			drift := func(state float64, rng *rand.Rand) float64 {
+				return state + 1 + 0.3*rng.NormFloat64()
+			}
+			cfg := &ParticleFilterConfig{NParticles: 2000, Resampling: method, BinWidth: 0.1, Seed: 3}
+
+			f, err := NewParticleFilter(prior.Pmf, drift, cfg)
+			require.Nil(t, err)
+			pmfs, err := f.Filter(obs)
+			require.Nil(t, err)
+
+			for step, p := range pmfs {
+				mean, err := p.Mean()
+				require.Nil(t, err)
+				assert.InDelta(t, truth[step], mean, 0.5)
+			}
+		})
+	}
+}
+
+func TestParticleFilterZeroLikelihood(t *testing.T) {
+	t.Run("observation impossible for all particles", func(t *testing.T) {
+		prior := NewSuite(Uniform(NewBound(1, 4))...)
+		f, err := NewParticleFilter(prior.Pmf, staticTransition, &ParticleFilterConfig{NParticles: 10, Seed: 1})
+		require.Nil(t, err)
+
+		_, err = f.Filter([]SuiteObservation{&suiteTestObservation{10}})
+		require.NotNil(t, err)
+	})
+
+	t.Run("recovers after failed step", func(t *testing.T) {
+		prior := NewSuite(Uniform(NewBound(1, 4))...)
+		f, err := NewParticleFilter(prior.Pmf, staticTransition, &ParticleFilterConfig{NParticles: 10, Seed: 1})
+		require.Nil(t, err)
+		before := f.Pmf()
+
+		_, err = f.Step(&suiteTestObservation{10})
+		require.NotNil(t, err)
+		assert.Equal(t, before.Items(), f.Pmf().Items())
+
+		p, err := f.Step(LikelihoodFunc(func(float64) float64 { return 1 }))
+		require.Nil(t, err)
+		assert.InDelta(t, 1.0, getSum(probMap(p)), float64EqualTol)
+	})
+}
+
+func TestSystematicResample(t *testing.T) {
+	tests := map[string]struct {
+		weights  []float64
+		expected []int
+	}{
+		"uniform weights": {
+			weights:  []float64{0.25, 0.25, 0.25, 0.25},
+			expected: []int{0, 1, 2, 3},
+		},
+		"single nonzero weight": {
+			weights:  []float64{0, 1, 0, 0},
+			expected: []int{1, 1, 1, 1},
+		},
+		"proportional weights": {
+			weights:  []float64{0.5, 0, 0.25, 0.25},
+			expected: []int{0, 0, 2, 3},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			idxs := systematicResample(test.weights, rand.New(rand.NewSource(1)))
+
+			assert.Equal(t, test.expected, idxs)
+		})
+	}
+}
+
+func TestMultinomialResample(t *testing.T) {
+	t.Run("draws follow weights", func(t *testing.T) {
+		// every fourth particle has 10 times the weight of the others
+		weights := make([]float64, 10000)
+		for i := range weights {
+			weights[i] = 1
+			if i%4 == 0 {
+				weights[i] = 10
+			}
+		}
+		normalizeWeights(weights)
+
+		idxs := multinomialResample(weights, rand.New(rand.NewSource(1)))
+
+		heavy := 0
+		for _, idx := range idxs {
+			if idx%4 == 0 {
+				heavy++
+			}
+		}
+		expected := 2500.0 * 10 / (2500*10 + 7500)
+		assert.InDelta(t, expected, float64(heavy)/float64(len(idxs)), 0.02)
+	})
+}
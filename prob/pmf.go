@@ -2,12 +2,19 @@ package prob
 
 import (
 	"fmt"
+	"io"
+	"iter"
+	"math/rand"
+	"os"
+	"slices"
+	"sort"
+	"sync"
 )
 
 // PmfElement is a discrete element in a NumericPmf
 type PmfElement struct {
-	Val  float64
-	Prob float64
+	Val  float64 `json:"value"`
+	Prob float64 `json:"prob"`
 }
 
 // NewPmfElement creates a new NumericPmfElement
@@ -20,19 +27,56 @@ func NewPmfElement(val float64, prob float64) *PmfElement {
 
 // Pmf is a probability mass function
 type Pmf struct {
-	prob map[float64]float64
+	// vals are stored in increasing order with probs[i] the probability of vals[i]
+	vals  []float64
+	probs []float64
+
+	// cumulative sums and cdf are computed lazily and reset on mutation;
+	// mu guards them so that concurrent reads are safe
+	mu     sync.Mutex
+	cumsum []float64
+	cdf    *Cdf
 }
 
 // NewPmf creates a new Pmf
 func NewPmf() *Pmf {
 	return &Pmf{
-		prob: map[float64]float64{},
+		vals:  []float64{},
+		probs: []float64{},
+	}
+}
+
+// newPmfFromMap creates a new Pmf from a map of values to probabilities
+func newPmfFromMap(m map[float64]float64) *Pmf {
+	p := &Pmf{
+		vals:  sortKeys(m),
+		probs: make([]float64, 0, len(m)),
+	}
+	for _, val := range p.vals {
+		p.probs = append(p.probs, m[val])
 	}
+	return p
 }
 
 // Set sets the value of an element
 func (p *Pmf) Set(elem *PmfElement) {
-	p.prob[elem.Val] = elem.Prob
+	defer p.invalidate()
+
+	// fast path for elements set in increasing order
+	n := len(p.vals)
+	if n == 0 || elem.Val > p.vals[n-1] {
+		p.vals = append(p.vals, elem.Val)
+		p.probs = append(p.probs, elem.Prob)
+		return
+	}
+
+	i, ok := p.index(elem.Val)
+	if ok {
+		p.probs[i] = elem.Prob
+		return
+	}
+	p.vals = slices.Insert(p.vals, i, elem.Val)
+	p.probs = slices.Insert(p.probs, i, elem.Prob)
 }
 
 // Normalize normalizes the values of the Pmf to sum to 1
@@ -40,7 +84,7 @@ func (p *Pmf) Normalize() {
 	// recompute sum each time rather than maintain it for simplicity
 	// and to match ThinkBayes implementation
 	sum := 0.0
-	for _, prob := range p.prob {
+	for _, prob := range p.probs {
 		sum += prob
 	}
 
@@ -48,50 +92,170 @@ func (p *Pmf) Normalize() {
 		return
 	}
 
-	for elem := range p.prob {
-		p.prob[elem] /= sum
+	for i := range p.probs {
+		p.probs[i] /= sum
 	}
+	p.invalidate()
 }
 
 // Mult multiplies the probability associated with an element by the specified value
 func (p *Pmf) Mult(val float64, multFactor float64) {
-	if _, ok := p.prob[val]; !ok {
+	i, ok := p.index(val)
+	if !ok {
 		// TODO: log a warning, print for now
 		fmt.Printf("attempting to modify nonexisting value [%v]\n", val)
 		return
 	}
-	p.prob[val] *= multFactor
+	p.probs[i] *= multFactor
+	p.invalidate()
 }
 
 // Prob returns the probability associated with an element
 func (p *Pmf) Prob(val float64) float64 {
-	pr, ok := p.prob[val]
+	i, ok := p.index(val)
 	if !ok {
 		return 0
 	}
-	return pr
+	return p.probs[i]
+}
+
+// Values returns the values of the Pmf in increasing order
+func (p *Pmf) Values() []float64 {
+	return slices.Clone(p.vals)
+}
+
+// Items returns the elements of the Pmf sorted by value
+func (p *Pmf) Items() []*PmfElement {
+	items := make([]*PmfElement, 0, len(p.vals))
+	for i, val := range p.vals {
+		items = append(items, NewPmfElement(val, p.probs[i]))
+	}
+	return items
+}
+
+// All returns an iterator over the values and probabilities of the Pmf in increasing order of value
+func (p *Pmf) All() iter.Seq2[float64, float64] {
+	return func(yield func(float64, float64) bool) {
+		for i, val := range p.vals {
+			if !yield(val, p.probs[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Copy returns a copy of the Pmf
+func (p *Pmf) Copy() *Pmf {
+	return &Pmf{
+		vals:  slices.Clone(p.vals),
+		probs: slices.Clone(p.probs),
+	}
+}
+
+// Map returns a new Pmf with the function f applied to each value;
+// probabilities of values mapping to the same result are summed
+func (p *Pmf) Map(f func(float64) float64) *Pmf {
+	m := map[float64]float64{}
+	for i, val := range p.vals {
+		m[f(val)] += p.probs[i]
+	}
+	return newPmfFromMap(m)
+}
+
+// Scale returns a new Pmf with each value multiplied by the specified factor
+func (p *Pmf) Scale(factor float64) *Pmf {
+	return p.Map(func(val float64) float64 {
+		return val * factor
+	})
+}
+
+// Shift returns a new Pmf with the specified offset added to each value
+func (p *Pmf) Shift(offset float64) *Pmf {
+	return p.Map(func(val float64) float64 {
+		return val + offset
+	})
+}
+
+// Filter returns a new normalized Pmf conditioned on values satisfying the predicate
+func (p *Pmf) Filter(pred func(float64) bool) (*Pmf, error) {
+	f := NewPmf()
+	sum := 0.0
+	for i, val := range p.vals {
+		if pred(val) {
+			f.vals = append(f.vals, val)
+			f.probs = append(f.probs, p.probs[i])
+			sum += p.probs[i]
+		}
+	}
+
+	if sum == 0 {
+		return f, fmt.Errorf("unable to filter pmf: no values with nonzero probability satisfy predicate")
+	}
+	f.Normalize()
+	return f, nil
+}
+
+// Truncate returns a new normalized Pmf conditioned on values within the bound
+func (p *Pmf) Truncate(b *Bound) (*Pmf, error) {
+	return p.Filter(func(val float64) bool {
+		return val >= float64(b.Low) && val <= float64(b.High)
+	})
+}
+
+// ProbLess returns the probability that a value drawn from the Pmf is less than
+// an independent value drawn from other
+func (p *Pmf) ProbLess(other *Pmf) float64 {
+	return PmfProbLess(p, other)
+}
+
+// ProbGreater returns the probability that a value drawn from the Pmf is greater than
+// an independent value drawn from other
+func (p *Pmf) ProbGreater(other *Pmf) float64 {
+	return PmfProbGreater(p, other)
+}
+
+// ProbEqual returns the probability that a value drawn from the Pmf is equal to
+// an independent value drawn from other
+func (p *Pmf) ProbEqual(other *Pmf) float64 {
+	return PmfProbEqual(p, other)
+}
+
+// ToNamedPmf transforms a Pmf to a NamedPmf using a function mapping each value to a name;
+// names are inserted in increasing order of value and probabilities of values mapping to
+// the same name are summed
+func (p *Pmf) ToNamedPmf(f func(float64) string) *NamedPmf {
+	named := NewNamedPmf()
+	for val, prob := range p.All() {
+		named.Incr(f(val), prob)
+	}
+	return named
 }
 
 // Print prints the Pmf
 func (p *Pmf) Print() {
+	p.Fprint(os.Stdout)
+}
+
+// Fprint prints the Pmf to w
+func (p *Pmf) Fprint(w io.Writer) {
 	border := "----------"
-	fmt.Println(border)
-	for val, prob := range p.prob {
-		fmt.Printf("%v: %f\n", val, prob)
+	fmt.Fprintln(w, border)
+	for val, prob := range p.All() {
+		fmt.Fprintf(w, "%v: %f\n", val, prob)
 	}
-	fmt.Println(border)
-	fmt.Println()
+	fmt.Fprintln(w, border)
+	fmt.Fprintln(w)
 }
 
 // Mean computes the mean of the Pmf
 func (p *Pmf) Mean() (float64, error) {
-	if len(p.prob) == 0 {
+	if len(p.vals) == 0 {
 		return 0.0, fmt.Errorf("unable to compute mean of empty pmf")
 	}
 
 	total := 0.0
-	for val, pr := range p.prob {
-		total += val * pr
+	for i, val := range p.vals {
+		total += val * p.probs[i]
 	}
 	return total, nil
 }
@@ -101,25 +265,30 @@ func (p *Pmf) Percentile(percentile float64) (float64, error) {
 	if percentile < 0 || percentile > 1 {
 		return 0, fmt.Errorf("percentile [%f] is outside of required range [0, 1]", percentile)
 	}
-	if len(p.prob) == 0 {
+	if len(p.vals) == 0 {
 		return 0, fmt.Errorf("cannot compute percentile of empty Pmf")
 	}
 
-	total := 0.0
-	for _, val := range sortKeys(p.prob) {
-		total += p.prob[val]
-		if total >= percentile {
-			return val, nil
+	cumsum := p.cumulative()
+	i := sort.Search(len(cumsum), func(i int) bool {
+		return cumsum[i] >= percentile
+	})
+	if i == len(cumsum) {
+		// guard against rounding leaving the final cumulative sum of a normalized Pmf just below 1
+		last := len(cumsum) - 1
+		if percentile-cumsum[last] < cdfTotalTol {
+			return p.vals[last], nil
 		}
+		return 0, fmt.Errorf("unable to compute percentile, potentially unnormalized Pmf")
 	}
-
-	return 0, fmt.Errorf("unable to compute percentile, potentially unnormalized Pmf")
+	return p.vals[i], nil
 }
 
 // MaximumLikelihood returns the value with the highest probability
 func (p *Pmf) MaximumLikelihood() (maxVal float64, err error) {
 	maxProb := 0.0
-	for val, prob := range p.prob {
+	// iterate in sorted order so that ties resolve to the smallest value
+	for val, prob := range p.All() {
 		if prob > maxProb {
 			maxProb = prob
 			maxVal = val
@@ -134,6 +303,26 @@ func (p *Pmf) MaximumLikelihood() (maxVal float64, err error) {
 	return maxVal, nil
 }
 
+// Random draws a random value from the distribution
+func (p *Pmf) Random(rng *rand.Rand) (float64, error) {
+	if len(p.vals) == 0 {
+		return 0, fmt.Errorf("cannot draw from empty Pmf")
+	}
+	cumsum := p.cumulative()
+	total := cumsum[len(cumsum)-1]
+	if total <= 0 {
+		return 0, fmt.Errorf("cannot draw from Pmf when all elements have probability 0")
+	}
+
+	u := rng.Float64() * total
+	i := sort.Search(len(cumsum), func(i int) bool {
+		return cumsum[i] > u
+	})
+	// guard against rounding in the final cumulative sum
+	i = min(i, len(cumsum)-1)
+	return p.vals[i], nil
+}
+
 // CredibleInterval computes the lower and upper bounds of a credible interval of specified length
 func (p *Pmf) CredibleInterval(l float64) (float64, float64, error) {
 	return CredibleInterval(p, l)
@@ -141,6 +330,57 @@ func (p *Pmf) CredibleInterval(l float64) (float64, float64, error) {
 
 // MakeCdf transforms a Pmf to a Cdf
 func (p *Pmf) MakeCdf() (*Cdf, error) {
-	c, err := NewCdf(p.prob)
-	return c, err
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cdf == nil {
+		c, err := newCdfFromSorted(p.vals, p.probs)
+		if err != nil {
+			return c, err
+		}
+		p.cdf = c
+	}
+	// return a copy so that callers cannot modify the cached cdf
+	return p.cdf.copy(), nil
+}
+
+// index returns the position of a value, or the position at which it would be inserted and false
+func (p *Pmf) index(val float64) (int, bool) {
+	i := sort.SearchFloat64s(p.vals, val)
+	return i, i < len(p.vals) && p.vals[i] == val
+}
+
+// remove removes a value
+func (p *Pmf) remove(val float64) {
+	i, ok := p.index(val)
+	if !ok {
+		return
+	}
+	p.vals = slices.Delete(p.vals, i, i+1)
+	p.probs = slices.Delete(p.probs, i, i+1)
+	p.invalidate()
+}
+
+// cumulative returns the (unnormalized) cumulative sums of the probabilities
+func (p *Pmf) cumulative() []float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cumsum != nil {
+		return p.cumsum
+	}
+	cumsum := make([]float64, len(p.probs))
+	total := 0.0
+	for i, prob := range p.probs {
+		total += prob
+		cumsum[i] = total
+	}
+	p.cumsum = cumsum
+	return cumsum
+}
+
+// invalidate resets values computed lazily from the probabilities
+func (p *Pmf) invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cumsum = nil
+	p.cdf = nil
 }
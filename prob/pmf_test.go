@@ -1,6 +1,9 @@
 package prob
 
 import (
+	"iter"
+	"math/rand"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,6 +21,19 @@ func setupPmf(elems []*PmfElement) *Pmf {
 	return p
 }
 
+type valueProbIterator interface {
+	All() iter.Seq2[float64, float64]
+}
+
+// probMap returns the probabilities of a Pmf keyed by value
+func probMap(p valueProbIterator) map[float64]float64 {
+	m := map[float64]float64{}
+	for val, prob := range p.All() {
+		m[val] = prob
+	}
+	return m
+}
+
 func getSum(m map[float64]float64) float64 {
 	sum := 0.0
 	for _, p := range m {
@@ -30,7 +46,7 @@ func TestNewPmf(t *testing.T) {
 	t.Run("new Pmf", func(t *testing.T) {
 		p := NewPmf()
 
-		assert.Empty(t, p.prob)
+		assert.Empty(t, probMap(p))
 	})
 }
 
@@ -55,8 +71,8 @@ func TestSet(t *testing.T) {
 			p := setupPmf(test.elements)
 
 			for _, elem := range test.elements {
-				require.Contains(t, p.prob, elem.Val)
-				assert.Equal(t, elem.Prob, p.prob[elem.Val])
+				require.Contains(t, probMap(p), elem.Val)
+				assert.Equal(t, elem.Prob, probMap(p)[elem.Val])
 			}
 		})
 	}
@@ -133,13 +149,13 @@ func TestNormalize(t *testing.T) {
 
 			p.Normalize()
 
-			for elem, prob := range p.prob {
+			for elem, prob := range probMap(p) {
 				assert.Equal(t, test.expectedProb[elem], prob)
 			}
 			if test.expectedSum == 0 {
-				assert.Equal(t, test.expectedSum, getSum(p.prob))
+				assert.Equal(t, test.expectedSum, getSum(probMap(p)))
 			} else {
-				assert.InEpsilon(t, test.expectedSum, getSum(p.prob), float64EqualTol)
+				assert.InEpsilon(t, test.expectedSum, getSum(probMap(p)), float64EqualTol)
 			}
 		})
 	}
@@ -175,20 +191,20 @@ func TestMult(t *testing.T) {
 			p := setupPmf(test.elements)
 
 			// store original probability before mutating
-			origProb, found := p.prob[test.val]
+			origProb, found := probMap(p)[test.val]
 
 			p.Mult(test.val, test.multFactor)
 
 			// test probability of specified element correctly multiplied
 			if found {
-				assert.Equal(t, origProb*test.multFactor, p.prob[test.val])
+				assert.Equal(t, origProb*test.multFactor, probMap(p)[test.val])
 			}
 			// test other probabilities are unchanged
 			for _, element := range test.elements {
 				if element.Val == test.val {
 					continue
 				}
-				assert.Equal(t, element.Prob, p.prob[element.Val])
+				assert.Equal(t, element.Prob, probMap(p)[element.Val])
 			}
 		})
 	}
@@ -286,65 +302,55 @@ func TestPmfPercentile(t *testing.T) {
 			shouldErr:  true,
 		},
 		"percentile less than 0": {
-			pmf: &Pmf{
-				prob: map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1},
-			},
+			pmf:        newPmfFromMap(map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1}),
 			percentile: -0.5,
 			expected:   0,
 			shouldErr:  true,
 		},
 		"percentile greater than 1": {
-			pmf: &Pmf{
-				prob: map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1},
-			},
+			pmf:        newPmfFromMap(map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1}),
 			percentile: 5,
 			expected:   0,
 			shouldErr:  true,
 		},
 		"unnormalized pmf": {
-			pmf: &Pmf{
-				prob: map[float64]float64{1: 0.02, 2: 0.03, 3: 0.04, 4: 0.01},
-			},
+			pmf:        newPmfFromMap(map[float64]float64{1: 0.02, 2: 0.03, 3: 0.04, 4: 0.01}),
 			percentile: 0.5,
 			expected:   0,
 			shouldErr:  true,
 		},
 		"unnormalized pmf with sum 1": {
-			pmf: &Pmf{
-				prob: map[float64]float64{1: 0.02, 2: 0.03, 3: 0.04, 4: 0.01},
-			},
+			pmf:        newPmfFromMap(map[float64]float64{1: 0.02, 2: 0.03, 3: 0.04, 4: 0.01}),
 			percentile: 0.5,
 			expected:   0,
 			shouldErr:  true,
 		},
 		"percentile 0": {
-			pmf: &Pmf{
-				prob: map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1},
-			},
+			pmf:        newPmfFromMap(map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1}),
 			percentile: 0,
 			expected:   1,
 			shouldErr:  false,
 		},
 		"percentile 1": {
-			pmf: &Pmf{
-				prob: map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1},
-			},
+			pmf:        newPmfFromMap(map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1}),
 			percentile: 1,
 			expected:   4,
 			shouldErr:  false,
 		},
+		"percentile 1 with rounding": {
+			pmf:        NewSuite(Uniform(NewBound(1, 6))...).Pmf,
+			percentile: 1,
+			expected:   6,
+			shouldErr:  false,
+		},
 		"percentile 0.5": {
-			pmf: &Pmf{
-				prob: map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1},
-			},
+			pmf:        newPmfFromMap(map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1}),
 			percentile: 0.5,
 			expected:   2,
 			shouldErr:  false,
 		},
 		"percentile 0.51": {
-			pmf: &Pmf{
-				prob: map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1},
-			},
+			pmf:        newPmfFromMap(map[float64]float64{1: 0.2, 2: 0.3, 3: 0.4, 4: 0.1}),
 			percentile: 0.51,
 			expected:   3,
 			shouldErr:  false,
@@ -417,3 +423,494 @@ func TestMaximumLikelihood(t *testing.T) {
 		})
 	}
 }
+
+func TestValues(t *testing.T) {
+	tests := map[string]struct {
+		elements []*PmfElement
+		expected []float64
+	}{
+		"empty Pmf": {
+			elements: []*PmfElement{},
+			expected: []float64{},
+		},
+		"multiple elements": {
+			elements: []*PmfElement{
+				NewPmfElement(3, 0.2),
+				NewPmfElement(1, 0.5),
+				NewPmfElement(2.5, 0.3),
+			},
+			expected: []float64{1, 2.5, 3},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupPmf(test.elements)
+
+			assert.Equal(t, test.expected, p.Values())
+		})
+	}
+}
+
+func TestItems(t *testing.T) {
+	tests := map[string]struct {
+		elements []*PmfElement
+		expected []*PmfElement
+	}{
+		"empty Pmf": {
+			elements: []*PmfElement{},
+			expected: []*PmfElement{},
+		},
+		"multiple elements": {
+			elements: []*PmfElement{
+				NewPmfElement(3, 0.2),
+				NewPmfElement(1, 0.5),
+				NewPmfElement(2.5, 0.3),
+			},
+			expected: []*PmfElement{
+				NewPmfElement(1, 0.5),
+				NewPmfElement(2.5, 0.3),
+				NewPmfElement(3, 0.2),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupPmf(test.elements)
+
+			assert.Equal(t, test.expected, p.Items())
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	t.Run("iterates in sorted order", func(t *testing.T) {
+		p := setupPmf([]*PmfElement{
+			NewPmfElement(3, 0.2),
+			NewPmfElement(1, 0.5),
+			NewPmfElement(2.5, 0.3),
+		})
+
+		vals := []float64{}
+		probs := []float64{}
+		for val, prob := range p.All() {
+			vals = append(vals, val)
+			probs = append(probs, prob)
+		}
+
+		assert.Equal(t, []float64{1, 2.5, 3}, vals)
+		assert.Equal(t, []float64{0.5, 0.3, 0.2}, probs)
+	})
+
+	t.Run("stops early", func(t *testing.T) {
+		p := setupPmf([]*PmfElement{
+			NewPmfElement(3, 0.2),
+			NewPmfElement(1, 0.5),
+			NewPmfElement(2.5, 0.3),
+		})
+
+		vals := []float64{}
+		for val := range p.All() {
+			vals = append(vals, val)
+			break
+		}
+
+		assert.Equal(t, []float64{1}, vals)
+	})
+}
+
+func TestCopy(t *testing.T) {
+	t.Run("copy is independent of original", func(t *testing.T) {
+		p := setupPmf([]*PmfElement{
+			NewPmfElement(1, 0.25),
+			NewPmfElement(2, 0.75),
+		})
+
+		c := p.Copy()
+		assert.Equal(t, probMap(p), probMap(c))
+
+		c.Mult(1, 2)
+		assert.Equal(t, 0.25, p.Prob(1))
+		assert.Equal(t, 0.5, c.Prob(1))
+	})
+}
+
+func TestMap(t *testing.T) {
+	tests := map[string]struct {
+		elements []*PmfElement
+		f        func(float64) float64
+		expected map[float64]float64
+	}{
+		"empty Pmf": {
+			elements: []*PmfElement{},
+			f:        func(x float64) float64 { return x },
+			expected: map[float64]float64{},
+		},
+		"one to one": {
+			elements: []*PmfElement{
+				NewPmfElement(50, 0.25),
+				NewPmfElement(100, 0.75),
+			},
+			f:        func(x float64) float64 { return x / 100 },
+			expected: map[float64]float64{0.5: 0.25, 1: 0.75},
+		},
+		"collisions are merged": {
+			elements: []*PmfElement{
+				NewPmfElement(-1, 0.25),
+				NewPmfElement(1, 0.5),
+				NewPmfElement(2, 0.25),
+			},
+			f:        func(x float64) float64 { return x * x },
+			expected: map[float64]float64{1: 0.75, 4: 0.25},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupPmf(test.elements)
+
+			m := p.Map(test.f)
+
+			assert.Equal(t, test.expected, probMap(m))
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	t.Run("scale", func(t *testing.T) {
+		p := setupPmf([]*PmfElement{
+			NewPmfElement(1, 0.25),
+			NewPmfElement(2, 0.75),
+		})
+
+		s := p.Scale(3)
+
+		assert.Equal(t, map[float64]float64{3: 0.25, 6: 0.75}, probMap(s))
+	})
+}
+
+func TestShift(t *testing.T) {
+	t.Run("shift", func(t *testing.T) {
+		p := setupPmf([]*PmfElement{
+			NewPmfElement(1, 0.25),
+			NewPmfElement(2, 0.75),
+		})
+
+		s := p.Shift(-1)
+
+		assert.Equal(t, map[float64]float64{0: 0.25, 1: 0.75}, probMap(s))
+	})
+}
+
+func TestFilter(t *testing.T) {
+	tests := map[string]struct {
+		elements  []*PmfElement
+		pred      func(float64) bool
+		expected  map[float64]float64
+		shouldErr bool
+	}{
+		"empty Pmf": {
+			elements:  []*PmfElement{},
+			pred:      func(x float64) bool { return true },
+			shouldErr: true,
+		},
+		"no values satisfy predicate": {
+			elements: []*PmfElement{
+				NewPmfElement(1, 0.5),
+				NewPmfElement(2, 0.5),
+			},
+			pred:      func(x float64) bool { return x > 2 },
+			shouldErr: true,
+		},
+		"only zero probability values satisfy predicate": {
+			elements: []*PmfElement{
+				NewPmfElement(1, 1),
+				NewPmfElement(2, 0),
+			},
+			pred:      func(x float64) bool { return x >= 2 },
+			shouldErr: true,
+		},
+		"condition on event": {
+			elements: []*PmfElement{
+				NewPmfElement(1, 0.5),
+				NewPmfElement(2, 0.25),
+				NewPmfElement(3, 0.25),
+			},
+			pred:      func(x float64) bool { return x >= 2 },
+			expected:  map[float64]float64{2: 0.5, 3: 0.5},
+			shouldErr: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupPmf(test.elements)
+
+			f, err := p.Filter(test.pred)
+
+			if test.shouldErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, test.expected, probMap(f))
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	p := setupPmf([]*PmfElement{
+		NewPmfElement(1, 0.5),
+		NewPmfElement(2, 0.25),
+		NewPmfElement(3, 0.125),
+		NewPmfElement(4, 0.125),
+	})
+
+	f, err := p.Truncate(NewBound(2, 3))
+	require.Nil(t, err)
+	assert.Equal(t, map[float64]float64{2: 2.0 / 3, 3: 1.0 / 3}, probMap(f))
+
+	_, err = p.Truncate(NewBound(5, 6))
+	require.NotNil(t, err)
+}
+
+func TestProbCompare(t *testing.T) {
+	tests := map[string]struct {
+		p               []*PmfElement
+		other           []*PmfElement
+		expectedLess    float64
+		expectedGreater float64
+		expectedEqual   float64
+	}{
+		"empty Pmf": {
+			p:               []*PmfElement{},
+			other:           []*PmfElement{NewPmfElement(1, 1)},
+			expectedLess:    0,
+			expectedGreater: 0,
+			expectedEqual:   0,
+		},
+		"point masses": {
+			p:               []*PmfElement{NewPmfElement(1, 1)},
+			other:           []*PmfElement{NewPmfElement(2, 1)},
+			expectedLess:    1,
+			expectedGreater: 0,
+			expectedEqual:   0,
+		},
+		"overlapping supports": {
+			p: []*PmfElement{
+				NewPmfElement(1, 0.5),
+				NewPmfElement(2, 0.5),
+			},
+			other: []*PmfElement{
+				NewPmfElement(2, 0.5),
+				NewPmfElement(3, 0.5),
+			},
+			expectedLess:    0.75,
+			expectedGreater: 0,
+			expectedEqual:   0.25,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := setupPmf(test.p)
+			other := setupPmf(test.other)
+
+			assert.Equal(t, test.expectedLess, p.ProbLess(other))
+			assert.Equal(t, test.expectedGreater, p.ProbGreater(other))
+			assert.Equal(t, test.expectedEqual, p.ProbEqual(other))
+		})
+	}
+}
+
+func TestToNamedPmf(t *testing.T) {
+	t.Run("names inserted in order of value and collisions merged", func(t *testing.T) {
+		p := setupPmf([]*PmfElement{
+			NewPmfElement(3, 0.2),
+			NewPmfElement(1, 0.5),
+			NewPmfElement(2, 0.3),
+		})
+
+		named := p.ToNamedPmf(func(val float64) string {
+			if val < 3 {
+				return "small"
+			}
+			return "large"
+		})
+
+		assert.Equal(t, []string{"small", "large"}, named.Names())
+		assert.Equal(t, 0.8, named.Prob("small"))
+		assert.Equal(t, 0.2, named.Prob("large"))
+	})
+}
+
+func TestSetMaintainsOrder(t *testing.T) {
+	t.Run("values set out of order are stored in increasing order", func(t *testing.T) {
+		p := setupPmf([]*PmfElement{
+			NewPmfElement(3, 0.1),
+			NewPmfElement(1, 0.2),
+			NewPmfElement(4, 0.3),
+			NewPmfElement(2, 0.4),
+			NewPmfElement(1, 0.5),
+		})
+
+		assert.Equal(t, []float64{1, 2, 3, 4}, p.vals)
+		assert.Equal(t, []float64{0.5, 0.4, 0.1, 0.3}, p.probs)
+	})
+}
+
+func TestLazyValuesInvalidated(t *testing.T) {
+	mutations := map[string]func(p *Pmf){
+		"Set": func(p *Pmf) {
+			p.Set(NewPmfElement(1, 0.75))
+		},
+		"Mult": func(p *Pmf) {
+			p.Mult(1, 3)
+		},
+		"Normalize": func(p *Pmf) {
+			p.probs[0] = 0.75 // modify without invalidating to isolate Normalize
+			p.Normalize()
+		},
+	}
+
+	for name, mutate := range mutations {
+		t.Run(name, func(t *testing.T) {
+			p := setupPmf([]*PmfElement{
+				NewPmfElement(1, 0.25),
+				NewPmfElement(2, 0.25),
+			})
+
+			median, err := p.Percentile(0.5)
+			require.Nil(t, err)
+			require.Equal(t, 2.0, median)
+			c, err := p.MakeCdf()
+			require.Nil(t, err)
+			cdfMedian, err := c.Percentile(0.5)
+			require.Nil(t, err)
+			require.Equal(t, 1.0, cdfMedian)
+
+			mutate(p)
+			p.Normalize()
+
+			median, err = p.Percentile(0.5)
+			require.Nil(t, err)
+			assert.Equal(t, 1.0, median)
+			c, err = p.MakeCdf()
+			require.Nil(t, err)
+			cdfMedian, err = c.Percentile(0.75)
+			require.Nil(t, err)
+			assert.Equal(t, 1.0, cdfMedian)
+		})
+	}
+}
+
+const benchmarkPmfSize = 100000
+
+func setupBenchmarkPmf() *Pmf {
+	p := NewPmf()
+	for i := 0; i < benchmarkPmfSize; i++ {
+		p.Set(NewPmfElement(float64(i), float64(i%7+1)))
+	}
+	return p
+}
+
+func BenchmarkNormalize(b *testing.B) {
+	p := setupBenchmarkPmf()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Normalize()
+	}
+}
+
+func BenchmarkPercentile(b *testing.B) {
+	p := setupBenchmarkPmf()
+	p.Normalize()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = p.Percentile(float64(i%100) / 100)
+	}
+}
+
+func BenchmarkPercentileAfterMult(b *testing.B) {
+	p := setupBenchmarkPmf()
+	p.Normalize()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Mult(float64(i%benchmarkPmfSize), 1)
+		_, _ = p.Percentile(0.5)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Run("empty Pmf", func(t *testing.T) {
+		_, err := NewPmf().Random(rand.New(rand.NewSource(1)))
+		require.NotNil(t, err)
+	})
+
+	t.Run("all zero probabilities", func(t *testing.T) {
+		p := setupPmf([]*PmfElement{NewPmfElement(1, 0)})
+		_, err := p.Random(rand.New(rand.NewSource(1)))
+		require.NotNil(t, err)
+	})
+
+	t.Run("draws follow probabilities", func(t *testing.T) {
+		p := setupPmf([]*PmfElement{
+			NewPmfElement(1, 0.2),
+			NewPmfElement(2, 0),
+			NewPmfElement(3, 0.8),
+		})
+		rng := rand.New(rand.NewSource(1))
+
+		n := 10000
+		counts := map[float64]int{}
+		for i := 0; i < n; i++ {
+			val, err := p.Random(rng)
+			require.Nil(t, err)
+			counts[val]++
+		}
+
+		assert.NotContains(t, counts, 2.0)
+		assert.InDelta(t, 0.2, float64(counts[1])/float64(n), 0.02)
+		assert.InDelta(t, 0.8, float64(counts[3])/float64(n), 0.02)
+	})
+}
+
+func TestConcurrentReads(t *testing.T) {
+	s := NewSuite(Uniform(NewBound(1, 100))...)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			_, err := s.Percentile(0.5)
+			assert.Nil(t, err)
+			_, err = s.MakeCdf()
+			assert.Nil(t, err)
+			_, err = s.Random(rand.New(rand.NewSource(seed)))
+			assert.Nil(t, err)
+		}(int64(i))
+	}
+	wg.Wait()
+}
+
+func TestMakeCdfReturnsCopy(t *testing.T) {
+	p := setupPmf([]*PmfElement{
+		NewPmfElement(1, 0.5),
+		NewPmfElement(2, 0.5),
+	})
+
+	c, err := p.MakeCdf()
+	require.Nil(t, err)
+	require.Nil(t, c.UnmarshalJSON([]byte(`[{"value": 5, "cumProb": 1}]`)))
+
+	c, err = p.MakeCdf()
+	require.Nil(t, err)
+	median, err := c.Percentile(0.5)
+	require.Nil(t, err)
+	assert.Equal(t, 1.0, median)
+}
@@ -0,0 +1,149 @@
+package prob
+
+import (
+	"fmt"
+)
+
+// PriorFamily generates the hypotheses of a prior distribution from a setting of its parameter
+type PriorFamily func(param float64) ([]*PmfElement, error)
+
+// UpperBoundFamily returns a PriorFamily parameterized by the (integer) upper bound of a
+// distribution generated over a Bound with the specified lower bound, such as Uniform or Triangle
+func UpperBoundFamily(low int, gen func(*Bound) []*PmfElement) PriorFamily {
+	return func(param float64) ([]*PmfElement, error) {
+		high := int(param)
+		if float64(high) != param || high < low {
+			return nil, fmt.Errorf("upper bound [%v] must be an integer no less than [%d]", param, low)
+		}
+		return gen(NewBound(low, high)), nil
+	}
+}
+
+// PowerLawFamily returns a PriorFamily of power law distributions over a Bound parameterized by alpha
+func PowerLawFamily(b *Bound) PriorFamily {
+	return func(alpha float64) ([]*PmfElement, error) {
+		return PowerLaw(b, alpha), nil
+	}
+}
+
+// BetaAlphaFamily returns a PriorFamily of discretized Beta distributions with nPoints values in [0, 1]
+// parameterized by alpha with fixed beta
+func BetaAlphaFamily(beta float64, nPoints int) PriorFamily {
+	return func(alpha float64) ([]*PmfElement, error) {
+		return discretizedBeta(alpha, beta, nPoints)
+	}
+}
+
+// BetaBetaFamily returns a PriorFamily of discretized Beta distributions with nPoints values in [0, 1]
+// parameterized by beta with fixed alpha
+func BetaBetaFamily(alpha float64, nPoints int) PriorFamily {
+	return func(beta float64) ([]*PmfElement, error) {
+		return discretizedBeta(alpha, beta, nPoints)
+	}
+}
+
+func discretizedBeta(alpha float64, beta float64, nPoints int) ([]*PmfElement, error) {
+	b, err := NewBeta(alpha, beta)
+	if err != nil {
+		return nil, err
+	}
+	return b.MakePmf(nPoints).Items(), nil
+}
+
+// Linspace returns n evenly spaced values from low to high inclusive
+func Linspace(low float64, high float64, n int) []float64 {
+	if n < 1 {
+		return []float64{}
+	}
+	if n == 1 {
+		return []float64{low}
+	}
+	vals := make([]float64, 0, n)
+	step := (high - low) / float64(n-1)
+	for i := 0; i < n; i++ {
+		vals = append(vals, low+float64(i)*step)
+	}
+	return vals
+}
+
+// SensitivitySummary contains summary statistics of the posterior for a setting of a prior parameter
+type SensitivitySummary struct {
+	Param   float64 `json:"param"`
+	Mean    float64 `json:"mean"`
+	MAP     float64 `json:"map"`
+	Median  float64 `json:"median"`
+	CILower float64 `json:"ciLower"`
+	CIUpper float64 `json:"ciUpper"`
+	CIWidth float64 `json:"ciWidth"`
+}
+
+// SensitivityTable is a table of posterior summaries ordered by prior parameter setting
+type SensitivityTable []*SensitivitySummary
+
+// Sensitivity updates a Suite built from the prior family for each setting of its parameter with
+// the observations and summarizes each posterior using a credible interval of specified length
+func Sensitivity(family PriorFamily, params []float64, obs []SuiteObservation, ciLength float64) (SensitivityTable, error) {
+	table := SensitivityTable{}
+	for _, param := range params {
+		hypos, err := family(param)
+		if err != nil {
+			return table, fmt.Errorf("unable to generate prior for parameter [%v]: %v", param, err)
+		}
+		s := NewSuite(hypos...)
+		for _, ob := range obs {
+			s.Update(ob)
+		}
+
+		summary, err := summarizeSensitivity(s, param, ciLength)
+		if err != nil {
+			return table, fmt.Errorf("unable to summarize posterior for parameter [%v]: %v", param, err)
+		}
+		table = append(table, summary)
+	}
+	return table, nil
+}
+
+// Print prints the SensitivityTable
+func (t SensitivityTable) Print() {
+	border := "----------"
+	fmt.Println(border)
+	for _, row := range t {
+		fmt.Printf(
+			"param: %v, mean: %0.2f, median: %0.2f, CI: (%0.2f, %0.2f), CI width: %0.2f\n",
+			row.Param, row.Mean, row.Median, row.CILower, row.CIUpper, row.CIWidth,
+		)
+	}
+	fmt.Println(border)
+	fmt.Println()
+}
+
+func summarizeSensitivity(s *Suite, param float64, ciLength float64) (*SensitivitySummary, error) {
+	if cumsum := s.cumulative(); len(cumsum) == 0 || cumsum[len(cumsum)-1] == 0 {
+		return nil, fmt.Errorf("observations have zero probability under the prior")
+	}
+	mean, err := s.Mean()
+	if err != nil {
+		return nil, err
+	}
+	mle, err := s.MaximumLikelihood()
+	if err != nil {
+		return nil, err
+	}
+	median, err := s.Percentile(0.5)
+	if err != nil {
+		return nil, err
+	}
+	lower, upper, err := s.CredibleInterval(ciLength)
+	if err != nil {
+		return nil, err
+	}
+	return &SensitivitySummary{
+		Param:   param,
+		Mean:    mean,
+		MAP:     mle,
+		Median:  median,
+		CILower: lower,
+		CIUpper: upper,
+		CIWidth: upper - lower,
+	}, nil
+}
@@ -0,0 +1,179 @@
+package prob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinspace(t *testing.T) {
+	tests := map[string]struct {
+		low      float64
+		high     float64
+		n        int
+		expected []float64
+	}{
+		"zero": {
+			low:      1,
+			high:     2,
+			n:        0,
+			expected: []float64{},
+		},
+		"one": {
+			low:      1,
+			high:     2,
+			n:        1,
+			expected: []float64{1},
+		},
+		"many": {
+			low:      1,
+			high:     2,
+			n:        5,
+			expected: []float64{1, 1.25, 1.5, 1.75, 2},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Linspace(test.low, test.high, test.n))
+		})
+	}
+}
+
+func TestPriorFamilies(t *testing.T) {
+	tests := map[string]struct {
+		family    PriorFamily
+		param     float64
+		expected  []*PmfElement
+		shouldErr bool
+	}{
+		"upper bound": {
+			family:   UpperBoundFamily(2, Uniform),
+			param:    4,
+			expected: Uniform(NewBound(2, 4)),
+		},
+		"noninteger upper bound": {
+			family:    UpperBoundFamily(2, Uniform),
+			param:     4.5,
+			shouldErr: true,
+		},
+		"upper bound below lower bound": {
+			family:    UpperBoundFamily(2, Uniform),
+			param:     1,
+			shouldErr: true,
+		},
+		"power law": {
+			family:   PowerLawFamily(NewBound(1, 5)),
+			param:    2,
+			expected: PowerLaw(NewBound(1, 5), 2),
+		},
+		"beta alpha": {
+			family: BetaAlphaFamily(1, 3),
+			param:  2,
+			expected: []*PmfElement{
+				NewPmfElement(0, 0),
+				NewPmfElement(0.5, 0.5),
+				NewPmfElement(1, 1),
+			},
+		},
+		"invalid beta alpha": {
+			family:    BetaAlphaFamily(1, 3),
+			param:     0,
+			shouldErr: true,
+		},
+		"beta beta": {
+			family: BetaBetaFamily(1, 3),
+			param:  2,
+			expected: []*PmfElement{
+				NewPmfElement(0, 1),
+				NewPmfElement(0.5, 0.5),
+				NewPmfElement(1, 0),
+			},
+		},
+		"invalid beta beta": {
+			family:    BetaBetaFamily(1, 3),
+			param:     -1,
+			shouldErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			hypos, err := test.family(test.param)
+			if test.shouldErr {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, test.expected, hypos)
+		})
+	}
+}
+
+func TestSensitivity(t *testing.T) {
+	obs := []SuiteObservation{&suiteTestObservation{3}, &suiteTestObservation{2}}
+	params := []float64{4, 6}
+
+	table, err := Sensitivity(UpperBoundFamily(1, Uniform), params, obs, 50)
+	require.Nil(t, err)
+	require.Len(t, table, len(params))
+
+	for i, param := range params {
+		s := NewSuite(Uniform(NewBound(1, int(param)))...)
+		s.UpdateSet(obs)
+		mean, err := s.Mean()
+		require.Nil(t, err)
+		mle, err := s.MaximumLikelihood()
+		require.Nil(t, err)
+		median, err := s.Percentile(0.5)
+		require.Nil(t, err)
+		lower, upper, err := s.CredibleInterval(50)
+		require.Nil(t, err)
+
+		row := table[i]
+		assert.Equal(t, param, row.Param)
+		assert.InDelta(t, mean, row.Mean, float64EqualTol)
+		assert.Equal(t, mle, row.MAP)
+		assert.Equal(t, median, row.Median)
+		assert.Equal(t, lower, row.CILower)
+		assert.Equal(t, upper, row.CIUpper)
+		assert.Equal(t, upper-lower, row.CIWidth)
+	}
+
+	// a larger upper bound shifts the posterior mean upward
+	assert.Greater(t, table[1].Mean, table[0].Mean)
+}
+
+func TestSensitivityErrors(t *testing.T) {
+	tests := map[string]struct {
+		family   PriorFamily
+		params   []float64
+		obs      []SuiteObservation
+		ciLength float64
+	}{
+		"invalid parameter": {
+			family:   UpperBoundFamily(1, Uniform),
+			params:   []float64{4, 0.5},
+			ciLength: 90,
+		},
+		"zero probability observation": {
+			family:   UpperBoundFamily(1, Uniform),
+			params:   []float64{4},
+			obs:      []SuiteObservation{&suiteTestObservation{5}},
+			ciLength: 90,
+		},
+		"invalid credible interval": {
+			family:   UpperBoundFamily(1, Uniform),
+			params:   []float64{4},
+			ciLength: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Sensitivity(test.family, test.params, test.obs, test.ciLength)
+			assert.NotNil(t, err)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package prob
 
 import (
+	"context"
 	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -13,11 +15,12 @@ type SuiteObservation interface {
 // Suite is a suite of hypotheses with associated probabilities (a Pmf)
 type Suite struct {
 	*Pmf
+	parallelism int
 }
 
 // NewSuite creates a new Suite
 func NewSuite(hypos ...*PmfElement) *Suite {
-	s := &Suite{NewPmf()}
+	s := &Suite{Pmf: NewPmf()}
 	for _, hypo := range hypos {
 		s.Set(hypo)
 	}
@@ -25,12 +28,25 @@ func NewSuite(hypos ...*PmfElement) *Suite {
 	return s
 }
 
+// SetParallelism sets the number of workers used to evaluate likelihoods in Update and UpdateSet;
+// likelihoods are evaluated serially for values less than 2 (the default) and observations must
+// be safe for concurrent use otherwise
+func (s *Suite) SetParallelism(n int) {
+	s.parallelism = n
+}
+
 // Update updates the probabilities based on an observation
 func (s *Suite) Update(ob SuiteObservation) {
-	for hypoName := range s.prob {
-		like := ob.GetLikelihood(hypoName)
-		s.Mult(hypoName, like)
+	if s.parallelism > 1 {
+		s.updateParallel([]SuiteObservation{ob})
+		s.Normalize()
+		return
 	}
+
+	for i, hypo := range s.vals {
+		s.probs[i] *= ob.GetLikelihood(hypo)
+	}
+	s.invalidate()
 	s.Normalize()
 }
 
@@ -39,16 +55,87 @@ func (s *Suite) UpdateSet(obs []SuiteObservation) {
 	// iterate elements of obs in random order for numerical stability: avoids long runs
 	// of one observation that push the probability of the others to values very close to zero
 	rand.Seed(time.Now().UnixNano())
-	for _, i := range rand.Perm(len(obs)) {
+	perm := rand.Perm(len(obs))
+
+	if s.parallelism > 1 {
+		ordered := make([]SuiteObservation, 0, len(obs))
+		for _, i := range perm {
+			ordered = append(ordered, obs[i])
+		}
+		s.updateParallel(ordered)
+		s.Normalize()
+		return
+	}
+
+	for _, i := range perm {
 		ob := obs[i]
-		for hypoName := range s.prob {
-			like := ob.GetLikelihood(hypoName)
-			s.Mult(hypoName, like)
+		for j, hypo := range s.vals {
+			s.probs[j] *= ob.GetLikelihood(hypo)
 		}
 	}
+	s.invalidate()
 	s.Normalize()
 }
 
+// UpdateStream updates the probabilities based on each observation received from obs, in order of
+// arrival, until obs is closed or ctx is done; if rec is not nil, the prior is recorded as step 0
+// and the posterior is recorded after each observation
+func (s *Suite) UpdateStream(ctx context.Context, obs <-chan SuiteObservation, rec Recorder) error {
+	step := 0
+	if rec != nil {
+		if err := rec.Record(step, s); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ob, ok := <-obs:
+			if !ok {
+				return nil
+			}
+			s.Update(ob)
+			step++
+			if rec != nil {
+				if err := rec.Record(step, s); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// updateParallel multiplies the probability of each hypothesis by the likelihoods of the
+// observations, in order, without normalizing; contiguous ranges of hypotheses are partitioned
+// across workers
+func (s *Suite) updateParallel(obs []SuiteObservation) {
+	defer s.invalidate()
+	hypos, probs := s.vals, s.probs
+
+	nWorkers := min(s.parallelism, len(hypos))
+	if nWorkers == 0 {
+		return
+	}
+	chunkSize := (len(hypos) + nWorkers - 1) / nWorkers
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(hypos); start += chunkSize {
+		end := min(start+chunkSize, len(hypos))
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				for _, ob := range obs {
+					probs[i] *= ob.GetLikelihood(hypos[i])
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+}
+
 // NamedSuiteObservation is the interface that must be satisfied to update probabilities
 type NamedSuiteObservation interface {
 	GetLikelihood(string) float64
@@ -1,9 +1,12 @@
 package prob
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewSuite(t *testing.T) {
@@ -28,9 +31,9 @@ func TestNewSuite(t *testing.T) {
 			s := NewSuite(test.elements...)
 
 			for _, elem := range test.elements {
-				assert.Contains(t, s.prob, elem.Val)
+				assert.Contains(t, probMap(s), elem.Val)
 			}
-			assert.Equal(t, 1.0, getSum(s.prob))
+			assert.Equal(t, 1.0, getSum(probMap(s)))
 		})
 	}
 }
@@ -70,9 +73,9 @@ func TestSuiteUpdate(t *testing.T) {
 
 		for elem, prob := range expectedPosterior {
 			if prob == 0 {
-				assert.Equal(t, 0.0, s.prob[elem])
+				assert.Equal(t, 0.0, probMap(s)[elem])
 			} else {
-				assert.InEpsilon(t, prob, s.prob[elem], float64EqualTol)
+				assert.InEpsilon(t, prob, probMap(s)[elem], float64EqualTol)
 			}
 		}
 	})
@@ -98,14 +101,99 @@ func TestSuiteUpdateSet(t *testing.T) {
 
 		for elem, prob := range expectedPosterior {
 			if prob == 0 {
-				assert.Equal(t, 0.0, s.prob[elem])
+				assert.Equal(t, 0.0, probMap(s)[elem])
 			} else {
-				assert.InEpsilon(t, prob, s.prob[elem], float64EqualTol)
+				assert.InEpsilon(t, prob, probMap(s)[elem], float64EqualTol)
 			}
 		}
 	})
 }
 
+type errRecorder struct {
+	failStep int
+}
+
+func (r *errRecorder) Record(step int, s *Suite) error {
+	if step == r.failStep {
+		return fmt.Errorf("failed to record step %d", step)
+	}
+	return nil
+}
+
+func TestSuiteUpdateStream(t *testing.T) {
+	t.Run("suite UpdateStream", func(t *testing.T) {
+		obs := make(chan SuiteObservation, 2)
+		obs <- &suiteTestObservation{4}
+		obs <- &suiteTestObservation{4}
+		close(obs)
+
+		expectedPosterior := map[float64]float64{
+			2: 0.0,
+			3: 0.0,
+			4: 0.0625 / 0.1025,
+			5: 0.04 / 0.1025,
+		}
+
+		s := NewSuite(suiteUpdateHypos...)
+
+		err := s.UpdateStream(context.Background(), obs, nil)
+
+		require.Nil(t, err)
+		for elem, prob := range expectedPosterior {
+			if prob == 0 {
+				assert.Equal(t, 0.0, probMap(s)[elem])
+			} else {
+				assert.InEpsilon(t, prob, probMap(s)[elem], float64EqualTol)
+			}
+		}
+	})
+
+	t.Run("suite UpdateStream records each step", func(t *testing.T) {
+		obs := make(chan SuiteObservation, 2)
+		obs <- &suiteTestObservation{4}
+		obs <- &suiteTestObservation{5}
+		close(obs)
+
+		s := NewSuite(suiteUpdateHypos...)
+		h := NewSnapshotHistory()
+
+		err := s.UpdateStream(context.Background(), obs, h)
+
+		require.Nil(t, err)
+		assert.Equal(t, []int{0, 1, 2}, h.Steps())
+	})
+
+	t.Run("suite UpdateStream canceled", func(t *testing.T) {
+		obs := make(chan SuiteObservation)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		s := NewSuite(suiteUpdateHypos...)
+
+		err := s.UpdateStream(ctx, obs, nil)
+
+		require.ErrorIs(t, err, context.Canceled)
+		for _, hypo := range suiteUpdateHypos {
+			assert.Equal(t, 0.25, probMap(s)[hypo.Val])
+		}
+	})
+
+	t.Run("suite UpdateStream recorder error", func(t *testing.T) {
+		obs := make(chan SuiteObservation, 2)
+		obs <- &suiteTestObservation{4}
+		obs <- &suiteTestObservation{4}
+		close(obs)
+
+		s := NewSuite(suiteUpdateHypos...)
+
+		err := s.UpdateStream(context.Background(), obs, &errRecorder{failStep: 1})
+
+		require.NotNil(t, err)
+		// the second observation is not applied
+		assert.InEpsilon(t, 0.25/0.45, probMap(s)[4], float64EqualTol)
+	})
+}
+
 func TestNewNamedSuite(t *testing.T) {
 	tests := map[string]struct {
 		elements []*NamedPmfElement
@@ -129,9 +217,9 @@ func TestNewNamedSuite(t *testing.T) {
 
 			for _, elem := range test.elements {
 				assert.Contains(t, s.nameToIdx, elem.Name)
-				assert.Contains(t, s.pmf.prob, s.nameToIdx[elem.Name])
+				assert.Contains(t, probMap(s.pmf), s.nameToIdx[elem.Name])
 			}
-			assert.Equal(t, 1.0, getSum(s.pmf.prob))
+			assert.Equal(t, 1.0, getSum(probMap(s.pmf)))
 		})
 	}
 }
@@ -214,3 +302,126 @@ func TestNamedSuiteUpdateSet(t *testing.T) {
 		}
 	})
 }
+
+func TestSuiteUpdateParallel(t *testing.T) {
+	tests := map[string]struct {
+		parallelism int
+	}{
+		"parallelism 2": {
+			parallelism: 2,
+		},
+		"parallelism 3": {
+			parallelism: 3,
+		},
+		"parallelism greater than number of hypotheses": {
+			parallelism: 100,
+		},
+	}
+
+	obs := []*suiteTestObservation{{60}, {30}, {90}}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			serial := NewSuite(Uniform(NewBound(1, 200))...)
+			parallel := NewSuite(Uniform(NewBound(1, 200))...)
+			parallel.SetParallelism(test.parallelism)
+
+			for _, ob := range obs {
+				serial.Update(ob)
+				parallel.Update(ob)
+			}
+
+			// the per-hypothesis order of multiplication is preserved so results are identical
+			assert.Equal(t, serial.Items(), parallel.Items())
+		})
+	}
+}
+
+func TestSuiteUpdateSetParallel(t *testing.T) {
+	t.Run("suite UpdateSet parallel", func(t *testing.T) {
+
+		obs := []SuiteObservation{
+			&suiteTestObservation{4},
+			&suiteTestObservation{4},
+		}
+		expectedPosterior := map[float64]float64{
+			2: 0.0,
+			3: 0.0,
+			4: 0.0625 / 0.1025,
+			5: 0.04 / 0.1025,
+		}
+
+		s := NewSuite(suiteUpdateHypos...)
+		s.SetParallelism(2)
+
+		s.UpdateSet(obs)
+
+		for elem, prob := range expectedPosterior {
+			if prob == 0 {
+				assert.Equal(t, 0.0, probMap(s)[elem])
+			} else {
+				assert.InEpsilon(t, prob, probMap(s)[elem], float64EqualTol)
+			}
+		}
+
+		serial := NewSuite(suiteUpdateHypos...)
+		serial.UpdateSet(obs)
+		assert.Equal(t, serial.Items(), s.Items())
+	})
+
+	t.Run("parallel UpdateSet identical to serial", func(t *testing.T) {
+		obs := []SuiteObservation{}
+		for _, val := range []float64{60, 30, 90, 45, 120} {
+			obs = append(obs, &suiteTestObservation{val})
+		}
+
+		for _, parallelism := range []int{2, 3, 8} {
+			serial := NewSuite(Uniform(NewBound(1, 500))...)
+			serial.UpdateSet(obs)
+			parallel := NewSuite(Uniform(NewBound(1, 500))...)
+			parallel.SetParallelism(parallelism)
+			parallel.UpdateSet(obs)
+
+			assert.Equal(t, serial.Items(), parallel.Items(), "parallelism %d", parallelism)
+		}
+	})
+
+	t.Run("empty suite", func(t *testing.T) {
+		s := NewSuite()
+		s.SetParallelism(4)
+
+		s.UpdateSet([]SuiteObservation{&suiteTestObservation{4}})
+
+		assert.Empty(t, probMap(s))
+	})
+}
+
+func BenchmarkSuiteUpdateSet(b *testing.B) {
+	obs := []SuiteObservation{}
+	for i := 1; i <= 2000; i++ {
+		obs = append(obs, &suiteTestObservation{float64(i%500 + 1)})
+	}
+
+	for _, parallelism := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				s := NewSuite(Uniform(NewBound(1, 2000))...)
+				s.SetParallelism(parallelism)
+				b.StartTimer()
+
+				s.UpdateSet(obs)
+			}
+		})
+	}
+}
+
+func BenchmarkSuiteUpdate(b *testing.B) {
+	s := NewSuite(Uniform(NewBound(1, benchmarkPmfSize))...)
+	ob := &suiteTestObservation{1}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Update(ob)
+	}
+}
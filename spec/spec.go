@@ -0,0 +1,451 @@
+// Package spec runs problems described declaratively in JSON; other formats such as YAML are not supported
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/dkaslovsky/GoThinkBayes/prob"
+)
+
+// likelihood families
+const (
+	Binomial = "binomial"
+	Poisson  = "poisson"
+	Dice     = "dice"
+	Table    = "table"
+)
+
+// hypothesis generator families
+const (
+	Uniform  = "uniform"
+	Triangle = "triangle"
+	PowerLaw = "powerlaw"
+	Beta     = "beta"
+)
+
+// summaries
+const (
+	Mean              = "mean"
+	Median            = "median"
+	MaximumLikelihood = "map"
+	CredibleInterval  = "ci"
+)
+
+var defaultSummaries = []string{MaximumLikelihood, Mean, Median, CredibleInterval}
+
+const defaultCILength = 90.0
+
+// Spec is a declarative specification of a problem: a prior over hypotheses, a likelihood,
+// observations and the summaries of the posterior to report
+type Spec struct {
+	Name         string         `json:"name"`
+	Hypotheses   *Hypotheses    `json:"hypotheses"`
+	Likelihood   *Likelihood    `json:"likelihood"`
+	Observations []*Observation `json:"observations"`
+	Summaries    []string       `json:"summaries,omitempty"` // defaults to map, mean, median and ci
+	CILength     float64        `json:"ciLength,omitempty"`  // defaults to 90
+}
+
+// Hypotheses specifies the prior as exactly one of explicit elements, equally likely values or a generator
+type Hypotheses struct {
+	Elements  []*prob.PmfElement `json:"elements,omitempty"`
+	Values    []float64          `json:"values,omitempty"`
+	Generator *Generator         `json:"generator,omitempty"`
+}
+
+// Generator specifies a prior generated by a family of distributions
+type Generator struct {
+	Family string  `json:"family"`
+	Low    int     `json:"low,omitempty"`    // lower bound of uniform, triangle and powerlaw
+	High   int     `json:"high,omitempty"`   // upper bound of uniform, triangle and powerlaw
+	Alpha  float64 `json:"alpha,omitempty"`  // parameter of powerlaw and beta
+	Beta   float64 `json:"beta,omitempty"`   // parameter of beta
+	Points int     `json:"points,omitempty"` // number of values in [0, 1] of beta
+}
+
+// Likelihood specifies the likelihood of an observation given a hypothesis
+type Likelihood struct {
+	Family string `json:"family"`
+	// Scale multiplies each hypothesis before the likelihood is evaluated, e.g. 0.01 for a
+	// binomial likelihood over hypotheses in percent; defaults to 1
+	Scale float64 `json:"scale,omitempty"`
+	// Table maps each hypothesis to the probabilities of the outcomes of a table likelihood
+	Table map[string]map[string]float64 `json:"table,omitempty"`
+}
+
+// Observation is an observation of a value (poisson and dice), a number of successes in a number
+// of trials (binomial) or an outcome (table), repeated Count times
+type Observation struct {
+	Value     float64 `json:"value,omitempty"`
+	Successes int     `json:"successes,omitempty"`
+	Trials    int     `json:"trials,omitempty"`
+	Outcome   string  `json:"outcome,omitempty"`
+	Count     *int    `json:"count,omitempty"` // defaults to 1; 0 ignores the observation
+}
+
+// Load reads and validates a Spec in JSON format
+func Load(r io.Reader) (*Spec, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	s := &Spec{}
+	if err := dec.Decode(s); err != nil {
+		return nil, fmt.Errorf("unable to decode spec: %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// LoadFile reads and validates a Spec from a JSON file
+func LoadFile(path string) (*Spec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open spec: %v", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Validate checks that the Spec is complete and consistent
+func (s *Spec) Validate() error {
+	if s.Hypotheses == nil {
+		return fmt.Errorf("invalid spec [%s]: hypotheses are required", s.Name)
+	}
+	if err := s.Hypotheses.validate(); err != nil {
+		return fmt.Errorf("invalid spec [%s]: %v", s.Name, err)
+	}
+	if s.Likelihood == nil {
+		return fmt.Errorf("invalid spec [%s]: likelihood is required", s.Name)
+	}
+	if err := s.Likelihood.validate(); err != nil {
+		return fmt.Errorf("invalid spec [%s]: %v", s.Name, err)
+	}
+	for i, ob := range s.Observations {
+		if err := ob.validate(s.Likelihood.Family); err != nil {
+			return fmt.Errorf("invalid spec [%s]: observation %d: %v", s.Name, i, err)
+		}
+	}
+	for _, summary := range s.Summaries {
+		if !slices.Contains(defaultSummaries, summary) {
+			return fmt.Errorf("invalid spec [%s]: unknown summary [%s]", s.Name, summary)
+		}
+	}
+	if s.CILength < 0 || s.CILength > 100 {
+		return fmt.Errorf("invalid spec [%s]: credible interval length [%v] must be in (0, 100], or 0 for the default", s.Name, s.CILength)
+	}
+	return nil
+}
+
+func (h *Hypotheses) validate() error {
+	n := 0
+	if len(h.Elements) > 0 {
+		n++
+	}
+	if len(h.Values) > 0 {
+		n++
+	}
+	if h.Generator != nil {
+		n++
+	}
+	if n != 1 {
+		return fmt.Errorf("hypotheses must specify exactly one of elements, values or generator")
+	}
+	if h.Generator != nil {
+		return h.Generator.validate()
+	}
+
+	total := 0.0
+	for _, elem := range h.Elements {
+		if elem == nil {
+			return fmt.Errorf("hypothesis element is missing")
+		}
+		if elem.Prob < 0 || math.IsNaN(elem.Prob) || math.IsInf(elem.Prob, 0) {
+			return fmt.Errorf("probability [%v] of hypothesis [%v] must be non-negative and finite", elem.Prob, elem.Val)
+		}
+		total += elem.Prob
+	}
+	if len(h.Elements) > 0 && total == 0 {
+		return fmt.Errorf("probabilities of hypothesis elements must not all be zero")
+	}
+	return nil
+}
+
+func (g *Generator) validate() error {
+	switch g.Family {
+	case Uniform, Triangle, PowerLaw:
+		if g.Low > g.High {
+			return fmt.Errorf("generator lower bound [%d] exceeds upper bound [%d]", g.Low, g.High)
+		}
+		if g.Family == PowerLaw && g.Low < 1 {
+			return fmt.Errorf("powerlaw generator lower bound [%d] must be positive", g.Low)
+		}
+	case Beta:
+		if g.Alpha <= 0 || g.Beta <= 0 {
+			return fmt.Errorf("beta generator parameters [%v, %v] must be positive", g.Alpha, g.Beta)
+		}
+		if g.Points < 2 {
+			return fmt.Errorf("beta generator number of points [%d] must be at least 2", g.Points)
+		}
+	default:
+		return fmt.Errorf("unknown generator family [%s]", g.Family)
+	}
+	return nil
+}
+
+func (l *Likelihood) validate() error {
+	switch l.Family {
+	case Binomial, Poisson, Dice:
+	case Table:
+		if len(l.Table) == 0 {
+			return fmt.Errorf("table likelihood requires a table")
+		}
+		for hypo := range l.Table {
+			if _, err := strconv.ParseFloat(hypo, 64); err != nil {
+				return fmt.Errorf("table hypothesis [%s] is not a number", hypo)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown likelihood family [%s]", l.Family)
+	}
+	if l.Scale < 0 {
+		return fmt.Errorf("likelihood scale [%v] must be non-negative", l.Scale)
+	}
+	return nil
+}
+
+func (o *Observation) validate(family string) error {
+	if o.Count != nil && *o.Count < 0 {
+		return fmt.Errorf("count [%d] must be non-negative", *o.Count)
+	}
+	switch family {
+	case Binomial:
+		if o.Trials < 0 || o.Successes < 0 || o.Successes > o.Trials {
+			return fmt.Errorf("successes [%d] must be between 0 and trials [%d]", o.Successes, o.Trials)
+		}
+	case Poisson:
+		if o.Value < 0 || o.Value != math.Floor(o.Value) {
+			return fmt.Errorf("poisson value [%v] must be a non-negative integer", o.Value)
+		}
+	case Table:
+		if o.Outcome == "" {
+			return fmt.Errorf("table observation requires an outcome")
+		}
+	}
+	return nil
+}
+
+// Summary is a named summary statistic of a posterior
+type Summary struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// Result contains the posterior of a Spec and its requested summaries
+type Result struct {
+	Name      string     `json:"name"`
+	Posterior *prob.Pmf  `json:"posterior"`
+	Summaries []*Summary `json:"summaries"`
+	CILength  float64    `json:"ciLength"`
+}
+
+// Run updates a Suite over the hypotheses with the observations and summarizes the posterior
+func (s *Spec) Run() (*Result, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	hypos, err := s.Hypotheses.elements()
+	if err != nil {
+		return nil, fmt.Errorf("unable to run spec [%s]: %v", s.Name, err)
+	}
+	suite := prob.NewSuite(hypos...)
+
+	obs := make([]*prob.Counted, 0, len(s.Observations))
+	for _, ob := range s.Observations {
+		like, err := s.Likelihood.observation(ob)
+		if err != nil {
+			return nil, fmt.Errorf("unable to run spec [%s]: %v", s.Name, err)
+		}
+		count := 1
+		if ob.Count != nil {
+			count = *ob.Count
+		}
+		obs = append(obs, prob.NewCounted(like, count))
+	}
+	if err := suite.UpdateCounted(obs...); err != nil {
+		return nil, fmt.Errorf("unable to run spec [%s]: %v", s.Name, err)
+	}
+
+	summaries, err := s.summarize(suite)
+	if err != nil {
+		return nil, fmt.Errorf("unable to run spec [%s]: %v", s.Name, err)
+	}
+	return &Result{
+		Name:      s.Name,
+		Posterior: suite.Copy(),
+		Summaries: summaries,
+		CILength:  s.ciLength(),
+	}, nil
+}
+
+func (h *Hypotheses) elements() ([]*prob.PmfElement, error) {
+	switch {
+	case len(h.Elements) > 0:
+		return h.Elements, nil
+	case len(h.Values) > 0:
+		elems := make([]*prob.PmfElement, 0, len(h.Values))
+		for _, val := range h.Values {
+			elems = append(elems, prob.NewPmfElement(val, 1))
+		}
+		return elems, nil
+	}
+
+	g := h.Generator
+	b := prob.NewBound(g.Low, g.High)
+	switch g.Family {
+	case Uniform:
+		return prob.Uniform(b), nil
+	case Triangle:
+		return prob.Triangle(b), nil
+	case PowerLaw:
+		return prob.PowerLaw(b, g.Alpha), nil
+	default: // Beta
+		beta, err := prob.NewBeta(g.Alpha, g.Beta)
+		if err != nil {
+			return nil, err
+		}
+		return beta.MakePmf(g.Points).Items(), nil
+	}
+}
+
+func (l *Likelihood) observation(ob *Observation) (prob.SuiteObservation, error) {
+	scale := l.Scale
+	if scale == 0 {
+		scale = 1
+	}
+
+	switch l.Family {
+	case Binomial:
+		return prob.LikelihoodFunc(func(hypo float64) float64 {
+			return binomialLikelihood(ob.Successes, ob.Trials, hypo*scale)
+		}), nil
+	case Poisson:
+		return prob.LikelihoodFunc(func(hypo float64) float64 {
+			return poissonLikelihood(ob.Value, hypo*scale)
+		}), nil
+	case Dice:
+		return prob.LikelihoodFunc(func(hypo float64) float64 {
+			sides := hypo * scale
+			if ob.Value < 1 || ob.Value > sides {
+				return 0
+			}
+			return 1 / sides
+		}), nil
+	default: // Table
+		table := prob.LikelihoodTable{}
+		for hypo, outcomes := range l.Table {
+			val, err := strconv.ParseFloat(hypo, 64)
+			if err != nil {
+				return nil, fmt.Errorf("table hypothesis [%s] is not a number", hypo)
+			}
+			table[val] = outcomes
+		}
+		return table.Observe(ob.Outcome), nil
+	}
+}
+
+// binomialLikelihood is the probability of k successes in n trials with probability of success p
+func binomialLikelihood(k int, n int, p float64) float64 {
+	if p < 0 || p > 1 {
+		return 0
+	}
+	if p == 0 || p == 1 {
+		if (p == 0 && k == 0) || (p == 1 && k == n) {
+			return 1
+		}
+		return 0
+	}
+	lnChoose := lgamma(float64(n+1)) - lgamma(float64(k+1)) - lgamma(float64(n-k+1))
+	return math.Exp(lnChoose + float64(k)*math.Log(p) + float64(n-k)*math.Log(1-p))
+}
+
+// poissonLikelihood is the probability of k events with rate lambda
+func poissonLikelihood(k float64, lambda float64) float64 {
+	if lambda < 0 {
+		return 0
+	}
+	if lambda == 0 {
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}
+	return math.Exp(k*math.Log(lambda) - lambda - lgamma(k+1))
+}
+
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
+
+func (s *Spec) summarize(suite *prob.Suite) ([]*Summary, error) {
+	names := s.Summaries
+	if len(names) == 0 {
+		names = defaultSummaries
+	}
+	ciLength := s.ciLength()
+
+	summaries := []*Summary{}
+	for _, name := range names {
+		switch name {
+		case Mean:
+			mean, err := suite.Mean()
+			if err != nil {
+				return nil, err
+			}
+			summaries = append(summaries, &Summary{Name: Mean, Value: mean})
+		case Median:
+			median, err := suite.Percentile(0.5)
+			if err != nil {
+				return nil, err
+			}
+			summaries = append(summaries, &Summary{Name: Median, Value: median})
+		case MaximumLikelihood:
+			mle, err := suite.MaximumLikelihood()
+			if err != nil {
+				return nil, err
+			}
+			summaries = append(summaries, &Summary{Name: MaximumLikelihood, Value: mle})
+		case CredibleInterval:
+			lower, upper, err := suite.CredibleInterval(ciLength)
+			if err != nil {
+				return nil, err
+			}
+			summaries = append(summaries,
+				&Summary{Name: "ciLower", Value: lower},
+				&Summary{Name: "ciUpper", Value: upper},
+			)
+		}
+	}
+	return summaries, nil
+}
+
+func (s *Spec) ciLength() float64 {
+	if s.CILength == 0 {
+		return defaultCILength
+	}
+	return s.CILength
+}
+
+// Print prints the summaries of the Result
+func (r *Result) Print(w io.Writer) {
+	for _, summary := range r.Summaries {
+		fmt.Fprintf(w, "%s: %0.2f\n", summary.Name, summary.Value)
+	}
+}
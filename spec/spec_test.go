@@ -0,0 +1,272 @@
+package spec
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dkaslovsky/GoThinkBayes/prob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const float64EqualTol = 1e-9
+
+func summaryMap(r *Result) map[string]float64 {
+	m := map[string]float64{}
+	for _, summary := range r.Summaries {
+		m[summary.Name] = summary.Value
+	}
+	return m
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"malformed": `{"name": `,
+		"unknown field": `{
+			"name": "x", "foo": 1,
+			"hypotheses": {"values": [1]}, "likelihood": {"family": "dice"}
+		}`,
+		"missing hypotheses": `{"name": "x", "likelihood": {"family": "dice"}}`,
+		"multiple hypothesis sources": `{
+			"hypotheses": {"values": [1], "generator": {"family": "uniform", "low": 1, "high": 2}},
+			"likelihood": {"family": "dice"}
+		}`,
+		"no hypothesis source": `{"hypotheses": {}, "likelihood": {"family": "dice"}}`,
+		"negative element probability": `{
+			"hypotheses": {"elements": [{"value": 1, "prob": -1}, {"value": 2, "prob": 2}]},
+			"likelihood": {"family": "dice"}
+		}`,
+		"zero element probabilities": `{
+			"hypotheses": {"elements": [{"value": 1, "prob": 0}, {"value": 2, "prob": 0}]},
+			"likelihood": {"family": "dice"}
+		}`,
+		"missing element": `{
+			"hypotheses": {"elements": [null]},
+			"likelihood": {"family": "dice"}
+		}`,
+		"unknown generator": `{
+			"hypotheses": {"generator": {"family": "foo"}}, "likelihood": {"family": "dice"}
+		}`,
+		"invalid generator bounds": `{
+			"hypotheses": {"generator": {"family": "uniform", "low": 3, "high": 2}},
+			"likelihood": {"family": "dice"}
+		}`,
+		"invalid powerlaw lower bound": `{
+			"hypotheses": {"generator": {"family": "powerlaw", "low": 0, "high": 2, "alpha": 1}},
+			"likelihood": {"family": "dice"}
+		}`,
+		"invalid beta parameters": `{
+			"hypotheses": {"generator": {"family": "beta", "alpha": 0, "beta": 1, "points": 11}},
+			"likelihood": {"family": "binomial"}
+		}`,
+		"invalid beta points": `{
+			"hypotheses": {"generator": {"family": "beta", "alpha": 1, "beta": 1, "points": 1}},
+			"likelihood": {"family": "binomial"}
+		}`,
+		"missing likelihood": `{"hypotheses": {"values": [1]}}`,
+		"unknown likelihood": `{"hypotheses": {"values": [1]}, "likelihood": {"family": "foo"}}`,
+		"negative scale":     `{"hypotheses": {"values": [1]}, "likelihood": {"family": "dice", "scale": -1}}`,
+		"missing table":      `{"hypotheses": {"values": [1]}, "likelihood": {"family": "table"}}`,
+		"non-numeric table hypothesis": `{
+			"hypotheses": {"values": [1]},
+			"likelihood": {"family": "table", "table": {"a": {"b": 1}}}
+		}`,
+		"missing table outcome": `{
+			"hypotheses": {"values": [1]},
+			"likelihood": {"family": "table", "table": {"1": {"b": 1}}},
+			"observations": [{"value": 1}]
+		}`,
+		"too many successes": `{
+			"hypotheses": {"values": [0.5]},
+			"likelihood": {"family": "binomial"},
+			"observations": [{"successes": 3, "trials": 2}]
+		}`,
+		"non-integer poisson value": `{
+			"hypotheses": {"values": [1]},
+			"likelihood": {"family": "poisson"},
+			"observations": [{"value": 1.5}]
+		}`,
+		"negative count": `{
+			"hypotheses": {"values": [1]},
+			"likelihood": {"family": "dice"},
+			"observations": [{"value": 1, "count": -1}]
+		}`,
+		"unknown summary": `{
+			"hypotheses": {"values": [1]}, "likelihood": {"family": "dice"}, "summaries": ["foo"]
+		}`,
+		"invalid credible interval": `{
+			"hypotheses": {"values": [1]}, "likelihood": {"family": "dice"}, "ciLength": 101
+		}`,
+		"negative credible interval": `{
+			"hypotheses": {"values": [1]}, "likelihood": {"family": "dice"}, "ciLength": -1
+		}`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(strings.NewReader(input))
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestRunDice(t *testing.T) {
+	s, err := Load(strings.NewReader(`{
+		"name": "dice",
+		"hypotheses": {"values": [4, 6, 8, 12, 20]},
+		"likelihood": {"family": "dice"},
+		"observations": [{"value": 6, "count": 2}, {"value": 8}]
+	}`))
+	require.Nil(t, err)
+	result, err := s.Run()
+	require.Nil(t, err)
+
+	expected := map[float64]float64{}
+	total := 0.0
+	for _, sides := range []float64{8, 12, 20} {
+		expected[sides] = math.Pow(1/sides, 3)
+		total += expected[sides]
+	}
+	for val, pr := range result.Posterior.All() {
+		assert.InDelta(t, expected[val]/total, pr, float64EqualTol)
+	}
+
+	summaries := summaryMap(result)
+	assert.Equal(t, 8.0, summaries["map"])
+	assert.Equal(t, 8.0, summaries["median"])
+	assert.Contains(t, summaries, "mean")
+	assert.Contains(t, summaries, "ciLower")
+	assert.Contains(t, summaries, "ciUpper")
+	assert.Equal(t, 90.0, result.CILength)
+}
+
+func TestRunBinomial(t *testing.T) {
+	s := &Spec{
+		Name:         "euro",
+		Hypotheses:   &Hypotheses{Generator: &Generator{Family: Uniform, Low: 0, High: 100}},
+		Likelihood:   &Likelihood{Family: Binomial, Scale: 0.01},
+		Observations: []*Observation{{Successes: 140, Trials: 250}},
+		Summaries:    []string{MaximumLikelihood, Mean},
+	}
+	result, err := s.Run()
+	require.Nil(t, err)
+
+	suite := prob.NewSuite(prob.Uniform(prob.NewBound(0, 100))...)
+	suite.Update(prob.LikelihoodFunc(func(hypo float64) float64 {
+		p := hypo / 100
+		return math.Pow(p, 140) * math.Pow(1-p, 110)
+	}))
+	mean, err := suite.Mean()
+	require.Nil(t, err)
+
+	summaries := summaryMap(result)
+	assert.Equal(t, 56.0, summaries["map"])
+	assert.InDelta(t, mean, summaries["mean"], 1e-6)
+	assert.Len(t, summaries, 2)
+}
+
+func TestRunBeta(t *testing.T) {
+	s := &Spec{
+		Hypotheses:   &Hypotheses{Generator: &Generator{Family: Beta, Alpha: 1, Beta: 1, Points: 101}},
+		Likelihood:   &Likelihood{Family: Binomial},
+		Observations: []*Observation{{Successes: 1, Trials: 2}},
+		Summaries:    []string{MaximumLikelihood},
+	}
+	result, err := s.Run()
+	require.Nil(t, err)
+	assert.InDelta(t, 0.5, summaryMap(result)["map"], float64EqualTol)
+}
+
+func TestRunPoisson(t *testing.T) {
+	s := &Spec{
+		Hypotheses:   &Hypotheses{Values: []float64{1, 2, 3}},
+		Likelihood:   &Likelihood{Family: Poisson},
+		Observations: []*Observation{{Value: 2}, {Value: 3}},
+	}
+	result, err := s.Run()
+	require.Nil(t, err)
+
+	expected := map[float64]float64{}
+	total := 0.0
+	for _, lambda := range []float64{1, 2, 3} {
+		expected[lambda] = math.Pow(lambda, 5) * math.Exp(-2*lambda)
+		total += expected[lambda]
+	}
+	for val, pr := range result.Posterior.All() {
+		assert.InDelta(t, expected[val]/total, pr, float64EqualTol)
+	}
+}
+
+func TestRunTable(t *testing.T) {
+	s := &Spec{
+		Hypotheses: &Hypotheses{Elements: []*prob.PmfElement{
+			prob.NewPmfElement(1, 1),
+			prob.NewPmfElement(2, 1),
+		}},
+		Likelihood: &Likelihood{Family: Table, Table: map[string]map[string]float64{
+			"1": {"vanilla": 0.75, "chocolate": 0.25},
+			"2": {"vanilla": 0.5, "chocolate": 0.5},
+		}},
+		Observations: []*Observation{{Outcome: "vanilla"}},
+	}
+	result, err := s.Run()
+	require.Nil(t, err)
+	assert.InDelta(t, 0.6, result.Posterior.Prob(1), float64EqualTol)
+	assert.InDelta(t, 0.4, result.Posterior.Prob(2), float64EqualTol)
+}
+
+func TestRunZeroCount(t *testing.T) {
+	s, err := Load(strings.NewReader(`{
+		"hypotheses": {"values": [4, 6]},
+		"likelihood": {"family": "dice"},
+		"observations": [{"value": 6, "count": 0}]
+	}`))
+	require.Nil(t, err)
+	result, err := s.Run()
+	require.Nil(t, err)
+	assert.InDelta(t, 0.5, result.Posterior.Prob(4), float64EqualTol)
+	assert.InDelta(t, 0.5, result.Posterior.Prob(6), float64EqualTol)
+}
+
+func TestRunZeroProbability(t *testing.T) {
+	s := &Spec{
+		Hypotheses:   &Hypotheses{Values: []float64{4, 6}},
+		Likelihood:   &Likelihood{Family: Dice},
+		Observations: []*Observation{{Value: 7}},
+	}
+	_, err := s.Run()
+	assert.NotNil(t, err)
+}
+
+func TestLikelihoods(t *testing.T) {
+	assert.InDelta(t, 0.375, binomialLikelihood(1, 3, 0.5), float64EqualTol)
+	assert.Equal(t, 1.0, binomialLikelihood(0, 3, 0))
+	assert.Equal(t, 0.0, binomialLikelihood(1, 3, 0))
+	assert.Equal(t, 1.0, binomialLikelihood(3, 3, 1))
+	assert.Equal(t, 0.0, binomialLikelihood(1, 3, 1.5))
+
+	assert.InDelta(t, 2*math.Exp(-2), poissonLikelihood(1, 2), float64EqualTol)
+	assert.Equal(t, 1.0, poissonLikelihood(0, 0))
+	assert.Equal(t, 0.0, poissonLikelihood(1, 0))
+	assert.Equal(t, 0.0, poissonLikelihood(1, -1))
+}
+
+func TestExampleSpecs(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "specs", "*.json"))
+	require.Nil(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			s, err := LoadFile(path)
+			require.Nil(t, err)
+			_, err = s.Run()
+			assert.Nil(t, err)
+		})
+	}
+
+	_, err = LoadFile(filepath.Join("..", "specs", "missing.json"))
+	assert.NotNil(t, err)
+}